@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/matthewhartstonge/configurator/diag"
+)
+
+// Diagnostic codes reported when validating the example app's config.
+const (
+	CodePortOutOfRange          diag.Code = "EXAMPLE1001"
+	CodeBackupFrequencyNegative diag.Code = "EXAMPLE1002"
+)
+
+func init() {
+	diag.MustRegisterCode(
+		diag.CodeInfo{
+			Code:        CodePortOutOfRange,
+			Name:        "port-out-of-range",
+			Description: "The configured port is not a valid TCP port.",
+			Suggestion:  "Configure a port between 0 and 65535.",
+		},
+		diag.CodeInfo{
+			Code:        CodeBackupFrequencyNegative,
+			Name:        "backup-frequency-negative",
+			Description: "The configured backup frequency is negative.",
+			Suggestion:  "Configure the backup frequency as a non-negative number of hours.",
+		},
+	)
+}
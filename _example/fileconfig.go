@@ -8,7 +8,11 @@ import (
 	"github.com/matthewhartstonge/configurator/diag"
 )
 
-var _ configurator.ConfigImplementer = (*ExampleFileConfig)(nil)
+var (
+	_ configurator.ConfigImplementer          = (*ExampleFileConfig)(nil)
+	_ configurator.LocatingValidator          = (*ExampleFileConfig)(nil)
+	_ configurator.DomainMerger[DomainConfig] = (*ExampleFileConfig)(nil)
+)
 
 type ExampleFileConfig struct {
 	MyApp struct {
@@ -20,15 +24,23 @@ type ExampleFileConfig struct {
 }
 
 func (e *ExampleFileConfig) Validate(component diag.Component) *diag.Diagnostics {
+	return e.ValidateLocated(component, nil)
+}
+
+// ValidateLocated validates the config, pointing at the offending keys within
+// the config file, if the locator is able to find them.
+func (e *ExampleFileConfig) ValidateLocated(component diag.Component, locator configurator.KeyLocator) *diag.Diagnostics {
 	diags := new(diag.Diagnostics)
 	if e.MyApp.Port < 0 || e.MyApp.Port > 65535 {
-		diags.FromComponent(component, "myapp.port").
+		locate(diags.FromComponent(component, "myapp.port"), locator, "MyApp.Port").
+			Code(CodePortOutOfRange).
 			Error("Unable to parse port",
 				"Port must be between 0 and 65535, but instead got "+strconv.Itoa(e.MyApp.Port))
 		e.MyApp.Port = 0
 	}
 	if e.MyApp.BackupFrequency < 0 {
-		diags.FromComponent(component, "myapp.backupFrequency").
+		locate(diags.FromComponent(component, "myapp.backupFrequency"), locator, "MyApp.BackupFrequency").
+			Code(CodeBackupFrequencyNegative).
 			Error("Unable to parse backup frequency",
 				"Backup frequency should be provided in hours and should be non-negative, but got "+strconv.Itoa(e.MyApp.BackupFrequency))
 		e.MyApp.BackupFrequency = 0
@@ -37,9 +49,21 @@ func (e *ExampleFileConfig) Validate(component diag.Component) *diag.Diagnostics
 	return diags
 }
 
-func (e *ExampleFileConfig) Merge(d any) any {
-	cfg := d.(*DomainConfig)
+// locate attaches the source range of the field's key to the diagnostic, if
+// it can be found.
+func locate(b *diag.Builder, locator configurator.KeyLocator, field string) *diag.Builder {
+	if locator == nil {
+		return b
+	}
 
+	if rng, ok := locator.Locate(field); ok {
+		return b.At(rng)
+	}
+
+	return b
+}
+
+func (e *ExampleFileConfig) Merge(cfg *DomainConfig) {
 	if e.MyApp.Name != "" {
 		cfg.Name = e.MyApp.Name
 	}
@@ -52,6 +76,4 @@ func (e *ExampleFileConfig) Merge(d any) any {
 	if e.MyApp.Version != "" {
 		cfg.Version = e.MyApp.Version
 	}
-
-	return cfg
 }
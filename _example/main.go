@@ -3,9 +3,11 @@ package main
 import (
 	stdjson "encoding/json"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/matthewhartstonge/configurator"
+	"github.com/matthewhartstonge/configurator/diag"
 	"github.com/matthewhartstonge/configurator/env/envconfig"
 	"github.com/matthewhartstonge/configurator/file/hcl"
 	"github.com/matthewhartstonge/configurator/file/json"
@@ -29,9 +31,11 @@ func main() {
 		Version:         "0.0.0",
 	}
 
-	cfg := &configurator.Config{
-		AppName: "ExampleApp",
-		Domain:  defaults,
+	cfg := &configurator.Config[DomainConfig]{
+		Options: configurator.Options{
+			AppName: "ExampleApp",
+		},
+		Domain: defaults,
 		File: []configurator.ConfigFileTypeable{
 			yaml.New(&ExampleFileConfig{}),
 			toml.New(&ExampleFileConfig{}),
@@ -39,7 +43,7 @@ func main() {
 			hcl.New(&ExampleFileConfig{}),
 		},
 		Env:  envconfig.New(&ExampleEnvConfig{}),
-		Flag: stdflag.New(&ExampleFlagConfig{}),
+		Flag: stdflag.NewFlagSet(&ExampleFlagConfig{}),
 	}
 
 	// Calling `New` implicitly parses the configuration. If you want to
@@ -52,7 +56,9 @@ func main() {
 	// Our diagnostics can be read to see every little step taken, file read and
 	// what wasn't able to parse.
 	if diags.Len() > 0 {
-		fmt.Printf("\nConfiguration diagnostics:\n%s\n\n", diags.All())
+		fmt.Println("\nConfiguration diagnostics:")
+		_ = diag.NewTerminalRenderer(os.Stdout).Render(os.Stdout, diags)
+		fmt.Println()
 	}
 
 	// If we want to read the underlying processed values for every file, envvar
@@ -64,6 +70,16 @@ func main() {
 		fmt.Printf("Parsed %s config at %s with values: %+v\n", v.Component, v.Path, v.Value)
 	}
 
+	// If we want to know why a value ended up the way it did, we can ask the
+	// app config to explain where the value was set from and which values it
+	// overrode along the way.
+	if p, ok := appConfig.Explain("port"); ok {
+		fmt.Printf("\n%s was set to %v by %s %s\n", p.Field, p.Value, p.Component, p.Path)
+		for _, o := range p.Overridden {
+			fmt.Printf("  overriding %v set by %s %s\n", o.Value, o.Component, o.Path)
+		}
+	}
+
 	// Pretty print the merged config to console!
 	mergedConfig, _ := stdjson.MarshalIndent(appConfig.Domain, "", "  ")
 	fmt.Printf("\nMerged Config:\n%s\n", string(mergedConfig))
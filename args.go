@@ -0,0 +1,162 @@
+package configurator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/matthewhartstonge/configurator/diag"
+)
+
+// argsTerminator ends flag parsing, every argument that follows is positional.
+const argsTerminator = "--"
+
+// flagArg describes an occurrence of a flag found by scanFlag.
+type flagArg struct {
+	// index is the index of the flag within the arguments.
+	index int
+	// span is the number of arguments the flag and its value occupy.
+	span int
+	// value is the value provided to the flag.
+	value string
+	// hasValue reports whether a value was provided to the flag.
+	hasValue bool
+}
+
+// scanFlag pre-scans the arguments for every occurrence of the named flag,
+// ahead of flag configurators parsing the arguments.
+//
+// Flags are matched by exact name with either a single or double dash, taking
+// a value with either `-flag=value` or `-flag value` syntax. As the flag
+// package does, scanning stops at the first positional argument, or a `--`
+// terminator, stepping over the values of other flags. isBool reports which of
+// the other flags are boolean, taking no value. If nil, an argument following
+// another flag is taken as its value, unless it looks like a flag.
+//
+// Neither an argument following the named flag that looks like a flag, for
+// example `-config-file -verbose`, nor a following `--` terminator, is
+// consumed as the value. Values that begin with a dash can be provided with
+// `-flag=value` syntax, while a lone `-` is always consumed as a value, as it
+// conventionally refers to stdin.
+func scanFlag(args []string, name string, isBool func(name string) bool) []flagArg {
+	var found []flagArg
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == argsTerminator || !isFlagArg(arg) {
+			// flags end at the terminator, or the first positional argument.
+			break
+		}
+
+		flagName, value, hasValue, ok := parseFlagArg(arg)
+		if !ok {
+			continue
+		}
+
+		if flagName != name {
+			if !hasValue && takesValue(args, i, flagName, isBool) {
+				// step over the other flag's value.
+				i++
+			}
+			continue
+		}
+
+		match := flagArg{index: i, span: 1, value: value, hasValue: hasValue}
+		if !hasValue && i+1 < len(args) && !isFlagArg(args[i+1]) && args[i+1] != argsTerminator {
+			match.value, match.hasValue = args[i+1], true
+			match.span++
+			i++
+		}
+
+		found = append(found, match)
+	}
+
+	return found
+}
+
+// takesValue reports whether the argument following the flag at index i is
+// the flag's value.
+func takesValue(args []string, i int, name string, isBool func(name string) bool) bool {
+	if i+1 >= len(args) {
+		return false
+	}
+	if isBool != nil {
+		return !isBool(name)
+	}
+
+	next := args[i+1]
+	return !isFlagArg(next) && next != argsTerminator
+}
+
+// parseFlagArg splits a flag argument into its name and any `=` provided
+// value, reporting whether the argument is a flag.
+func parseFlagArg(arg string) (name, value string, hasValue, ok bool) {
+	if !isFlagArg(arg) {
+		return "", "", false, false
+	}
+
+	name = strings.TrimPrefix(arg[1:], "-")
+	if name == "" || name[0] == '-' || name[0] == '=' {
+		// not a flag as understood by the flag package.
+		return "", "", false, false
+	}
+
+	name, value, hasValue = strings.Cut(name, "=")
+	return name, value, hasValue, true
+}
+
+// isFlagArg reports whether the argument looks like a flag.
+func isFlagArg(arg string) bool {
+	return len(arg) > 1 && arg[0] == '-' && arg != argsTerminator
+}
+
+// getFlagValue extracts the value of the named flag from the arguments,
+// reporting whether the flag was found. If provided more than once, the last
+// value wins, matching the flag package.
+func getFlagValue(diags *diag.Diagnostics, component diag.Component, args []string, name string, isBool func(name string) bool) (string, bool) {
+	values := getFlagValues(diags, component, args, name, isBool)
+	if len(values) == 0 {
+		return "", false
+	}
+
+	return values[len(values)-1], true
+}
+
+// getFlagValues extracts every value provided for a repeatable flag name from
+// the arguments, in argument order. An error diagnostic is reported for each
+// occurrence of the flag given without a value, or given an empty value, for
+// example `-flag=`.
+func getFlagValues(diags *diag.Diagnostics, component diag.Component, args []string, name string, isBool func(name string) bool) []string {
+	var values []string
+	for _, match := range scanFlag(args, name, isBool) {
+		value := strings.TrimSpace(match.value)
+		if value == "" {
+			detail := fmt.Sprintf("The -%s flag requires a value, either as -%s=value or -%s value", name, name, name)
+			if match.hasValue {
+				detail = fmt.Sprintf("The -%s flag was given an empty value, so has been ignored", name)
+			}
+
+			diags.FromComponent(component, "-"+name).
+				Code(CodeFlagMissingValue).
+				Error("CLI Flag Missing Value", detail)
+			continue
+		}
+
+		values = append(values, value)
+	}
+
+	return values
+}
+
+// removeFlagFromArgs returns a copy of the arguments with every occurrence of
+// the named flag, and its value, removed. Arguments following the first
+// positional argument, or a `--` terminator, are left untouched.
+func removeFlagFromArgs(args []string, name string, isBool func(name string) bool) []string {
+	newArgs := make([]string, 0, len(args))
+
+	prev := 0
+	for _, match := range scanFlag(args, name, isBool) {
+		newArgs = append(newArgs, args[prev:match.index]...)
+		prev = match.index + match.span
+	}
+
+	return append(newArgs, args[prev:]...)
+}
@@ -0,0 +1,201 @@
+package configurator
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/matthewhartstonge/configurator/diag"
+)
+
+func TestGetFlagValues(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		want        []string
+		isBool      func(name string) bool
+		wantMissing int
+		wantArgs    []string
+	}{
+		{
+			name:     "not provided",
+			args:     []string{"-verbose", "positional"},
+			want:     nil,
+			wantArgs: []string{"-verbose", "positional"},
+		},
+		{
+			name:     "space separated value",
+			args:     []string{"-config-file", "config.yaml", "-verbose"},
+			want:     []string{"config.yaml"},
+			wantArgs: []string{"-verbose"},
+		},
+		{
+			name:     "equals separated value",
+			args:     []string{"-config-file=config.yaml"},
+			want:     []string{"config.yaml"},
+			wantArgs: []string{},
+		},
+		{
+			name:     "double dash",
+			args:     []string{"--config-file", "config.yaml", "--config-file=other.yaml"},
+			want:     []string{"config.yaml", "other.yaml"},
+			wantArgs: []string{},
+		},
+		{
+			name:     "repeated in argument order",
+			args:     []string{"-config-file", "a.yaml", "-port", "80", "-config-file", "b.yaml"},
+			want:     []string{"a.yaml", "b.yaml"},
+			wantArgs: []string{"-port", "80"},
+		},
+		{
+			name:     "stdin",
+			args:     []string{"-config-file", "-", "-verbose"},
+			want:     []string{StdinPath},
+			wantArgs: []string{"-verbose"},
+		},
+		{
+			name:     "dash prefixed value with equals",
+			args:     []string{"-config-file=-weird.yaml"},
+			want:     []string{"-weird.yaml"},
+			wantArgs: []string{},
+		},
+		{
+			name:        "flag looking value isn't consumed",
+			args:        []string{"-config-file", "-verbose"},
+			want:        nil,
+			wantMissing: 1,
+			wantArgs:    []string{"-verbose"},
+		},
+		{
+			name:        "missing value at end",
+			args:        []string{"-verbose", "-config-file"},
+			want:        nil,
+			wantMissing: 1,
+			wantArgs:    []string{"-verbose"},
+		},
+		{
+			name:        "explicit empty value",
+			args:        []string{"-config-file="},
+			want:        nil,
+			wantMissing: 1,
+			wantArgs:    []string{},
+		},
+		{
+			name:        "blank value",
+			args:        []string{"-config-file", "  "},
+			want:        nil,
+			wantMissing: 1,
+			wantArgs:    []string{},
+		},
+		{
+			name:     "terminator stops scanning",
+			args:     []string{"-config-file", "a.yaml", "--", "-config-file", "b.yaml"},
+			want:     []string{"a.yaml"},
+			wantArgs: []string{"--", "-config-file", "b.yaml"},
+		},
+		{
+			name:        "terminator isn't consumed as a value",
+			args:        []string{"-config-file", "--", "-config-file", "b.yaml"},
+			want:        nil,
+			wantMissing: 1,
+			wantArgs:    []string{"--", "-config-file", "b.yaml"},
+		},
+		{
+			name:     "stops at the first positional argument",
+			args:     []string{"sub", "-config-file", "x.yaml"},
+			want:     nil,
+			wantArgs: []string{"sub", "-config-file", "x.yaml"},
+		},
+		{
+			name:     "stops at a lone dash",
+			args:     []string{"-", "-config-file", "x.yaml"},
+			want:     nil,
+			wantArgs: []string{"-", "-config-file", "x.yaml"},
+		},
+		{
+			name:     "other flag values aren't positional",
+			args:     []string{"-port", "80", "-config-file", "x.yaml", "sub"},
+			want:     []string{"x.yaml"},
+			wantArgs: []string{"-port", "80", "sub"},
+		},
+		{
+			name:     "boolean flags take no value",
+			args:     []string{"-verbose", "sub", "-config-file", "x.yaml"},
+			isBool:   func(name string) bool { return name == "verbose" },
+			want:     nil,
+			wantArgs: []string{"-verbose", "sub", "-config-file", "x.yaml"},
+		},
+		{
+			name:     "non-boolean flags take any value",
+			args:     []string{"-name", "-dashed", "-config-file", "x.yaml"},
+			isBool:   func(string) bool { return false },
+			want:     []string{"x.yaml"},
+			wantArgs: []string{"-name", "-dashed"},
+		},
+		{
+			name:     "similar flag names aren't matched",
+			args:     []string{"-config-files", "a.yaml", "-config", "b.yaml", "---config-file", "c.yaml"},
+			want:     nil,
+			wantArgs: []string{"-config-files", "a.yaml", "-config", "b.yaml", "---config-file", "c.yaml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := new(diag.Diagnostics)
+			got := getFlagValues(diags, diag.ComponentFlagFile, tt.args, "config-file", tt.isBool)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("getFlagValues() = %q, want %q", got, tt.want)
+			}
+
+			missing := 0
+			for d := range diags.Seq() {
+				if d.Code == CodeFlagMissingValue {
+					missing++
+				}
+			}
+			if missing != tt.wantMissing {
+				t.Errorf("getFlagValues() reported %d missing values, want %d", missing, tt.wantMissing)
+			}
+
+			gotArgs := removeFlagFromArgs(tt.args, "config-file", tt.isBool)
+			if !slices.Equal(gotArgs, tt.wantArgs) {
+				t.Errorf("removeFlagFromArgs() = %q, want %q", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestGetFlagValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		want   string
+		wantOK bool
+	}{
+		{
+			name: "not provided",
+			args: []string{"-verbose"},
+		},
+		{
+			name:   "last value wins",
+			args:   []string{"-config-format", "yaml", "-config-format=json"},
+			want:   "json",
+			wantOK: true,
+		},
+		{
+			name:   "empty value is ignored",
+			args:   []string{"-config-format", "yaml", "-config-format="},
+			want:   "yaml",
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := getFlagValue(new(diag.Diagnostics), diag.ComponentFlagFile, tt.args, "config-format", nil)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("getFlagValue() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
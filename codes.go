@@ -0,0 +1,253 @@
+package configurator
+
+import (
+	"github.com/matthewhartstonge/configurator/diag"
+)
+
+// Diagnostic codes reported by configurator. Codes are stable, so can be
+// matched on by operators and support runbooks regardless of changes to a
+// diagnostic's summary.
+const (
+	// Config file codes.
+	CodeFileNotFound              diag.Code = "CFG1001"
+	CodeFileFound                 diag.Code = "CFG1002"
+	CodeFileTypeSkipped           diag.Code = "CFG1003"
+	CodeFileUnreadable            diag.Code = "CFG1004"
+	CodeFileTypeUnsupported       diag.Code = "CFG1005"
+	CodeFileLayered               diag.Code = "CFG1006"
+	CodeProfileFileMerged         diag.Code = "CFG1007"
+	CodeDropInDirNotFound         diag.Code = "CFG1008"
+	CodeDropInFragmentMerged      diag.Code = "CFG1009"
+	CodeDropInFragmentUnsupported diag.Code = "CFG1010"
+	CodeUnknownKey                diag.Code = "CFG1011"
+
+	// Config path discovery codes.
+	CodePathsSearched    diag.Code = "CFG2001"
+	CodePathAdded        diag.Code = "CFG2002"
+	CodePathUnavailable  diag.Code = "CFG2003"
+	CodeUnknownComponent diag.Code = "CFG2004"
+	CodeSearchStopped    diag.Code = "CFG2005"
+	CodeXDGDirsUnset     diag.Code = "CFG2006"
+	CodeXDGHomeUnset     diag.Code = "CFG2007"
+	CodeXDGDirIgnored    diag.Code = "CFG2008"
+
+	// CLI flag codes.
+	CodeFileFlagSet      diag.Code = "CFG3001"
+	CodeFileFlagUnset    diag.Code = "CFG3002"
+	CodeFileFormatSet    diag.Code = "CFG3003"
+	CodeFlagMissingValue diag.Code = "CFG3004"
+	CodeFilePathInvalid  diag.Code = "CFG3005"
+	CodeProfilesSelected diag.Code = "CFG3006"
+	CodeProfilesActive   diag.Code = "CFG3007"
+
+	// Config pipeline codes.
+	CodePipeline         diag.Code = "CFG4001"
+	CodeUnknownSource    diag.Code = "CFG4002"
+	CodeNoConfigurator   diag.Code = "CFG4003"
+	CodeParseFailed      diag.Code = "CFG4004"
+	CodeMergeFailed      diag.Code = "CFG4005"
+	CodeNoMerger         diag.Code = "CFG4006"
+	CodeValidationFailed diag.Code = "CFG4007"
+	CodeReloadRejected   diag.Code = "CFG4008"
+	CodeSourceSkipped    diag.Code = "CFG4009"
+	CodeNothingMerged    diag.Code = "CFG4010"
+)
+
+func init() {
+	diag.MustRegisterCode(
+		diag.CodeInfo{
+			Code:        CodeFileNotFound,
+			Name:        "file-not-found",
+			Description: "A config file could not be found at the path searched.",
+			Suggestion:  "Check the config file exists at the path and is readable.",
+		},
+		diag.CodeInfo{
+			Code:        CodeFileFound,
+			Name:        "file-found",
+			Description: "A config file was found to be parsed.",
+		},
+		diag.CodeInfo{
+			Code:        CodeFileTypeSkipped,
+			Name:        "file-type-skipped",
+			Description: "A file parser skipped a config file with a file extension it doesn't support.",
+		},
+		diag.CodeInfo{
+			Code:        CodeFileUnreadable,
+			Name:        "file-unreadable",
+			Description: "A config file could not be read to determine its file type.",
+			Suggestion:  "Check the config file is readable.",
+		},
+		diag.CodeInfo{
+			Code:        CodeFileTypeUnsupported,
+			Name:        "file-type-unsupported",
+			Description: "No file parser supports the file type of a config file.",
+			Suggestion:  "Provide a file parser supporting the file type, or specify the file type with the -config-format flag.",
+		},
+		diag.CodeInfo{
+			Code:        CodeFileLayered,
+			Name:        "file-layered",
+			Description: "A config file was merged over any previous config file layers.",
+		},
+		diag.CodeInfo{
+			Code:        CodeProfileFileMerged,
+			Name:        "profile-file-merged",
+			Description: "A profile's overlay of a config file was merged.",
+		},
+		diag.CodeInfo{
+			Code:        CodeDropInDirNotFound,
+			Name:        "drop-in-dir-not-found",
+			Description: "No drop-in directory was found alongside a config path.",
+		},
+		diag.CodeInfo{
+			Code:        CodeDropInFragmentMerged,
+			Name:        "drop-in-fragment-merged",
+			Description: "A config fragment from a drop-in directory was merged.",
+		},
+		diag.CodeInfo{
+			Code:        CodeDropInFragmentUnsupported,
+			Name:        "drop-in-fragment-unsupported",
+			Description: "No file parser supports a config fragment within a drop-in directory.",
+			Suggestion:  "Rename the fragment with a supported file extension, or remove it from the drop-in directory.",
+		},
+		diag.CodeInfo{
+			Code:        CodeUnknownKey,
+			Name:        "unknown-key",
+			Description: "A config key isn't supported by the config.",
+			Suggestion:  "Correct the spelling of the key, or remove it.",
+		},
+		diag.CodeInfo{
+			Code:        CodePathsSearched,
+			Name:        "paths-searched",
+			Description: "A search path strategy was searched for config paths.",
+		},
+		diag.CodeInfo{
+			Code:        CodePathAdded,
+			Name:        "path-added",
+			Description: "A config path was added to be searched for config files.",
+		},
+		diag.CodeInfo{
+			Code:        CodePathUnavailable,
+			Name:        "path-unavailable",
+			Description: "A directory to search for config files could not be determined.",
+		},
+		diag.CodeInfo{
+			Code:        CodeUnknownComponent,
+			Name:        "unknown-component",
+			Description: "Config paths were requested for a component that isn't a file component.",
+		},
+		diag.CodeInfo{
+			Code:        CodeSearchStopped,
+			Name:        "search-stopped",
+			Description: "Searching parent directories for config files stopped.",
+		},
+		diag.CodeInfo{
+			Code:        CodeXDGDirsUnset,
+			Name:        "xdg-dirs-unset",
+			Description: "XDG_CONFIG_DIRS is not set, so defaults to /etc/xdg on platforms following the XDG specification.",
+		},
+		diag.CodeInfo{
+			Code:        CodeXDGHomeUnset,
+			Name:        "xdg-home-unset",
+			Description: "XDG_CONFIG_HOME is not set, so defaults to the user configuration directory.",
+		},
+		diag.CodeInfo{
+			Code:        CodeXDGDirIgnored,
+			Name:        "xdg-dir-ignored",
+			Description: "A relative XDG base directory was ignored, as required by the XDG specification.",
+			Suggestion:  "Set XDG_CONFIG_HOME and XDG_CONFIG_DIRS to absolute paths.",
+		},
+		diag.CodeInfo{
+			Code:        CodeFileFlagSet,
+			Name:        "file-flag-set",
+			Description: "A config file path was specified via the CLI.",
+		},
+		diag.CodeInfo{
+			Code:        CodeFileFlagUnset,
+			Name:        "file-flag-unset",
+			Description: "No config file path was specified via the CLI.",
+		},
+		diag.CodeInfo{
+			Code:        CodeFileFormatSet,
+			Name:        "file-format-set",
+			Description: "The file type of CLI specified config files was specified via the CLI.",
+		},
+		diag.CodeInfo{
+			Code:        CodeFlagMissingValue,
+			Name:        "flag-missing-value",
+			Description: "A CLI flag requiring a value was provided without one.",
+			Suggestion:  "Provide a value for the flag, using -flag=value syntax if the value begins with a dash.",
+		},
+		diag.CodeInfo{
+			Code:        CodeFilePathInvalid,
+			Name:        "file-path-invalid",
+			Description: "The absolute path of a CLI specified config file could not be determined.",
+			Suggestion:  "Provide an absolute path to the config file.",
+		},
+		diag.CodeInfo{
+			Code:        CodeProfilesSelected,
+			Name:        "profiles-selected",
+			Description: "Profiles were selected via an environment variable or CLI flag.",
+		},
+		diag.CodeInfo{
+			Code:        CodeProfilesActive,
+			Name:        "profiles-active",
+			Description: "Profile overlays will be merged for the active profiles.",
+		},
+		diag.CodeInfo{
+			Code:        CodePipeline,
+			Name:        "pipeline",
+			Description: "The config sources to be processed, in order of precedence.",
+		},
+		diag.CodeInfo{
+			Code:        CodeUnknownSource,
+			Name:        "unknown-source",
+			Description: "The configured precedence contains an unknown config source.",
+			Suggestion:  "Remove the unknown source from Options.Precedence.",
+		},
+		diag.CodeInfo{
+			Code:        CodeNoConfigurator,
+			Name:        "no-configurator",
+			Description: "No configurator was provided for a config source.",
+		},
+		diag.CodeInfo{
+			Code:        CodeParseFailed,
+			Name:        "parse-failed",
+			Description: "A config source failed to parse.",
+			Suggestion:  "Correct the config at the reported location.",
+		},
+		diag.CodeInfo{
+			Code:        CodeMergeFailed,
+			Name:        "merge-failed",
+			Description: "Parsed config values could not be merged into the domain config.",
+			Suggestion:  "Check the configurator struct tags map onto domain config fields of compatible types.",
+		},
+		diag.CodeInfo{
+			Code:        CodeNoMerger,
+			Name:        "no-merger",
+			Description: "A config implementer provides no way to merge into the domain config.",
+		},
+		diag.CodeInfo{
+			Code:        CodeValidationFailed,
+			Name:        "validation-failed",
+			Description: "A config implementer's Validate method reported an error without a code.",
+		},
+		diag.CodeInfo{
+			Code:        CodeReloadRejected,
+			Name:        "reload-rejected",
+			Description: "A reloaded configuration reported errors, so the previous configuration was kept.",
+			Suggestion:  "Correct the reported errors, the configuration will be reloaded once the config files change.",
+		},
+		diag.CodeInfo{
+			Code:        CodeSourceSkipped,
+			Name:        "source-skipped",
+			Description: "An explicitly requested config source won't be processed, as the precedence can't place it.",
+			Suggestion:  "Include the source in Options.Precedence, and remove it from Options.Disabled.",
+		},
+		diag.CodeInfo{
+			Code:        CodeNothingMerged,
+			Name:        "nothing-merged",
+			Description: "A config implementer neither implements a recognised Merge method, nor tags any fields to merge.",
+			Suggestion:  "Implement Merge(*Domain) on the config implementer, or tag its fields with the domain field to merge into, for example `configurator:\"Server.Port\"`.",
+		},
+	)
+}
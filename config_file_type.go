@@ -2,17 +2,20 @@ package configurator
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/matthewhartstonge/configurator/diag"
 )
 
 var (
-	_ ConfigParser      = (*ConfigFileType)(nil)
-	_ ConfigFileParser  = (*ConfigFileType)(nil)
-	_ ConfigImplementer = (*ConfigFileType)(nil)
+	_ ConfigParser         = (*ConfigFileType)(nil)
+	_ ConfigFileParser     = (*ConfigFileType)(nil)
+	_ ConfigFileTypeStater = (*ConfigFileType)(nil)
+	_ UnknownKeyChecker    = (*ConfigFileType)(nil)
+	_ ConfigImplementer    = (*ConfigFileType)(nil)
+	_ KeyLocator           = (*ConfigFileType)(nil)
 )
 
 // NewConfigFileType provides most functionality required to support a new file
@@ -34,6 +37,11 @@ func NewConfigFileType(
 // Unmarshaler unmarshals a byte slice into the given interface.
 type Unmarshaler func(data []byte, v interface{}) error
 
+// KeyRangeFinder returns the source range of the key within the config file
+// data that decodes into the field at the dotted Go field path of v,
+// reporting whether the key was found.
+type KeyRangeFinder func(data []byte, v any, field string) (diag.Range, bool)
+
 // ConfigFileType provides a ConfigImplementer that reads a file from disk and
 // unmarshals it into the Config field. Unmarshaling expects implementations to
 // match the standard library interface for Unmarshal.
@@ -46,6 +54,20 @@ type ConfigFileType struct {
 	// Types is a list of file types or dot extensions that the provider
 	// is able to process.
 	Types []string
+	// Strict configures how keys in the config file that are unknown to the
+	// config implementer are reported. Defaults to StrictIgnore.
+	Strict StrictMode
+	// FindUnknownKeys is provided by file type providers to find the keys in
+	// the config file unknown to the config implementer. If nil, unknown keys
+	// can't be reported.
+	FindUnknownKeys UnknownKeyFinder
+	// unknown stores the unknown keys found by the last parse.
+	unknown []UnknownKey
+	// FindKeyRange is provided by file type providers to locate keys within
+	// the config file. If nil, validators can't locate the keys they reject.
+	FindKeyRange KeyRangeFinder
+	// data stores the contents of the config file read by the last parse.
+	data []byte
 
 	// ConfigType is the embedded configurator.ConfigType.
 	ConfigType
@@ -57,9 +79,20 @@ func (f *ConfigFileType) Type() string {
 }
 
 // Stat checks if the file exists and computes the platform specific Path and
-// directly writes to the provided diagnostics.
-func (f *ConfigFileType) Stat(diags *diag.Diagnostics, component diag.Component, cfg *Config, filePath string) bool {
+// directly writes to the provided diagnostics. While a profile is being
+// processed, the profile's overlay, `<FileName>.<profile>.<ext>`, is stat'ed
+// instead of the base config file.
+func (f *ConfigFileType) Stat(diags *diag.Diagnostics, component diag.Component, opts *Options, filePath string) bool {
 	// todo: tidy `ConfigFileType.Stat` implementation. there should be a better way.
+	cfgFileName := opts.FileName
+	if opts.profile != "" {
+		// stat the profile's overlay of the config file instead.
+		cfgFileName += "." + opts.profile
+		if ext := filepath.Ext(filePath); ext != "" {
+			filePath = strings.TrimSuffix(filePath, ext) + "." + opts.profile + ext
+		}
+	}
+
 	filename := filepath.Base(filePath)
 	fileExt := filepath.Ext(filePath)
 	for _, fileType := range f.Types {
@@ -68,15 +101,19 @@ func (f *ConfigFileType) Stat(diags *diag.Diagnostics, component diag.Component,
 			if fileExt != "."+fileType {
 				// full file path provided, ext does match provider file type - skip.
 				diags.FromComponent(component, filePath).
+					Code(CodeFileTypeSkipped).
 					Trace("Skipping File Type",
 						"The file type does not match "+fileType)
 				continue
 			}
 
 			// stat full path!
-			info, err := os.Stat(filePath)
+			opts.addStatted(filePath)
+			info, err := opts.StatFile(filePath)
 			if err != nil {
 				diags.FromComponent(component, filePath).
+					Cause(err).
+					Code(CodeFileNotFound).
 					Trace("Config File Not Found",
 						"No config file was found at the specified path, error: "+err.Error())
 				return false
@@ -90,6 +127,7 @@ func (f *ConfigFileType) Stat(diags *diag.Diagnostics, component diag.Component,
 			// specified config file exists for the given file parser!
 			f.Path = filePath
 			diags.FromComponent(component, filePath).
+				Code(CodeFileFound).
 				Trace("Config File Found",
 					fmt.Sprintf("Will attempt to parse %s", filename))
 			return true
@@ -97,10 +135,12 @@ func (f *ConfigFileType) Stat(diags *diag.Diagnostics, component diag.Component,
 
 		// Dynamically build the expected config file path that can be parsed
 		// with this provider to check for files existence.
-		cfgFilePath := filePath + string(filepath.Separator) + cfg.FileName + "." + filePath
-		if _, err := os.Stat(cfgFilePath); err == nil {
+		cfgFilePath := filePath + string(filepath.Separator) + cfgFileName + "." + fileType
+		opts.addStatted(cfgFilePath)
+		if _, err := opts.StatFile(cfgFilePath); err == nil {
 			f.Path = cfgFilePath
 			diags.FromComponent(component, filePath).
+				Code(CodeFileFound).
 				Trace("Config File Found",
 					fmt.Sprintf("Will attempt to parse %s", cfgFilePath))
 			return true
@@ -108,18 +148,95 @@ func (f *ConfigFileType) Stat(diags *diag.Diagnostics, component diag.Component,
 	}
 
 	diags.FromComponent(component, filePath).
+		Code(CodeFileNotFound).
 		Trace("Config File Not Found",
 			fmt.Sprintf("Unable to find config file for extensions {%s} at %s", strings.Join(f.Types, ", "), filePath))
 	return false
 }
 
+// StatAs checks if the file exists, to be parsed as the given file type
+// regardless of the file's extension, and directly writes to the provided
+// diagnostics.
+func (f *ConfigFileType) StatAs(diags *diag.Diagnostics, component diag.Component, opts *Options, filePath string, fileType string) bool {
+	if !slices.Contains(f.Types, fileType) {
+		return false
+	}
+
+	opts.addStatted(filePath)
+	info, err := opts.StatFile(filePath)
+	if err != nil || info.IsDir() {
+		diags.FromComponent(component, filePath).
+			Code(CodeFileNotFound).
+			Trace("Config File Not Found",
+				fmt.Sprintf("No %s config file was found at the specified path", fileType))
+		return false
+	}
+
+	f.Path = filePath
+	diags.FromComponent(component, filePath).
+		Code(CodeFileFound).
+		Trace("Config File Found",
+			fmt.Sprintf("Will attempt to parse %s as %s", filepath.Base(filePath), fileType))
+	return true
+}
+
 // Parse reads the file based on the generated path computed from Stat and
 // unmarshals it into the Config field.
-func (f *ConfigFileType) Parse(_ *Config) (string, error) {
-	file, err := os.ReadFile(f.Path)
+func (f *ConfigFileType) Parse(opts *Options) (string, error) {
+	f.unknown = nil
+	f.data = nil
+
+	file, err := opts.ReadFile(f.Path)
 	if err != nil {
 		return f.Path, err
 	}
+	f.data = file
+
+	if err := f.unmarshaler(file, f.Config); err != nil {
+		return f.Path, err
+	}
+
+	if f.Strict != StrictIgnore && f.FindUnknownKeys != nil {
+		f.unknown, err = f.FindUnknownKeys(file, f.Config)
+		for _, key := range f.unknown {
+			if key.Range != nil && key.Range.Filename == "" {
+				key.Range.Filename = f.Path
+			}
+		}
+	}
+
+	return f.Path, err
+}
+
+// CheckUnknownKeys reports each key found by the last parse that is unknown
+// to the config implementer, returning false if the config file has been
+// rejected.
+func (f *ConfigFileType) CheckUnknownKeys(diags *diag.Diagnostics, component diag.Component) bool {
+	return f.Strict.Report(diags, component, f.Path, f.unknown)
+}
+
+// Locate returns the source range of the key, within the config file read by
+// the last parse, that decoded into the field at the dotted Go field path of
+// the config implementer, for example "MyApp.Port".
+func (f *ConfigFileType) Locate(field string) (diag.Range, bool) {
+	if f.FindKeyRange == nil || f.data == nil {
+		return diag.Range{}, false
+	}
+
+	rng, ok := f.FindKeyRange(f.data, f.Config, field)
+	if ok && rng.Filename == "" {
+		rng.Filename = f.Path
+	}
+
+	return rng, ok
+}
+
+// Validate validates the config implementer, providing the config file's key
+// locator to implementers of LocatingValidator.
+func (f *ConfigFileType) Validate(component diag.Component) *diag.Diagnostics {
+	if validator, ok := f.Config.(LocatingValidator); ok {
+		return validator.ValidateLocated(component, f)
+	}
 
-	return f.Path, f.unmarshaler(file, f.Config)
+	return f.ConfigType.Validate(component)
 }
@@ -12,5 +12,13 @@ type ConfigFileTypeable interface {
 
 type ConfigFileParser interface {
 	// Stat returns false if a file can't be found by the parser.
-	Stat(diags *diag.Diagnostics, component diag.Component, cfg *Config, dirPath string) bool
+	Stat(diags *diag.Diagnostics, component diag.Component, opts *Options, dirPath string) bool
+}
+
+// ConfigFileTypeStater is implemented by file parsers able to stat a file to
+// be parsed as a specific file type, regardless of the file's extension.
+type ConfigFileTypeStater interface {
+	// StatAs returns false if the parser doesn't support the file type, or the
+	// file can't be found by the parser.
+	StatAs(diags *diag.Diagnostics, component diag.Component, opts *Options, filePath string, fileType string) bool
 }
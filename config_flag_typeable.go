@@ -11,3 +11,13 @@ type ConfigFlagImplementer interface {
 	// Init is called to configure and set up the flag environment.
 	Init()
 }
+
+// BoolFlagReporter is implemented by flag configurators able to report which
+// of their flags are boolean, taking no value, so that flags processed by
+// configurator, such as the config file flag, are found within the arguments
+// exactly as the flag configurator parses them.
+type BoolFlagReporter interface {
+	// IsBoolFlag reports whether the named flag is a boolean flag. It is
+	// called once Init has defined the flags.
+	IsBoolFlag(name string) bool
+}
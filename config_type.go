@@ -2,7 +2,11 @@ package configurator
 
 import "github.com/matthewhartstonge/configurator/diag"
 
-var _ ConfigImplementer = (*ConfigType)(nil)
+var (
+	_ ConfigImplementer = (*ConfigType)(nil)
+	_ ConfigMerger      = (*ConfigType)(nil)
+	_ ConfigErrMerger   = (*ConfigType)(nil)
+)
 
 // ConfigType provides an abstract struct (née abstract base class) to compose
 // into concrete config parser implementations.
@@ -29,8 +33,25 @@ func (c *ConfigType) Validate(component diag.Component) *diag.Diagnostics {
 	return c.Config.Validate(component)
 }
 
-// Merge expects the implementor to merge the parsed configuration from the
-// concrete implementation and bind it back into the provided domain config.
+// Merge adapts untyped implementers by merging the parsed configuration from
+// the concrete implementation and binding it back into the provided domain
+// config. If the implementer does not implement ConfigMerger, the tagged
+// fields of the implementer are merged reflectively.
+//
+// Reflective merge errors are discarded, use MergeE to handle them.
+// Config.Parse merges via MergeE, reporting merge errors as diagnostics.
 func (c *ConfigType) Merge(domainConfig any) any {
-	return c.Config.Merge(domainConfig)
+	domainConfig, _ = c.MergeE(domainConfig)
+
+	return domainConfig
+}
+
+// MergeE merges the same as Merge, returning any reflective merge error, such
+// as a type mismatch, or a value overflowing the domain field.
+func (c *ConfigType) MergeE(domainConfig any) (any, error) {
+	if merger, ok := c.Config.(ConfigMerger); ok {
+		return merger.Merge(domainConfig), nil
+	}
+
+	return domainConfig, Merge(domainConfig, c.Config)
 }
@@ -14,12 +14,66 @@ type ConfigParser interface {
 	Type() string
 	// Parse returns the direct file path of the file that was parsed and any
 	// associated errors returned from parsing the file.
-	Parse(cfg *Config) (string, error)
+	Parse(opts *Options) (string, error)
 	// Values returns the current state of the configuration values.
 	Values() any
 }
 
 type ConfigImplementer interface {
 	Validate(component diag.Component) *diag.Diagnostics
+}
+
+// ConfigMerger is implemented by config implementers that merge their parsed
+// values into an untyped domain config. The domain config is passed in as the
+// pointer held in Config.Domain, and the merged result must be returned as the
+// same pointer type.
+type ConfigMerger interface {
 	Merge(config any) any
 }
+
+// ConfigErrMerger is implemented by config implementers whose merges can
+// fail, returning the merge error alongside the merged domain config rather
+// than discarding it. Config.Parse prefers MergeE over Merge.
+type ConfigErrMerger interface {
+	MergeE(config any) (any, error)
+}
+
+// DomainMerger is implemented by config implementers that merge their parsed
+// values directly into a typed domain config.
+type DomainMerger[T any] interface {
+	Merge(domain *T)
+}
+
+// UnknownKeyChecker is implemented by parsers able to report the keys found
+// while parsing that are unknown to the config implementer.
+type UnknownKeyChecker interface {
+	// CheckUnknownKeys returns false if the parsed config has been rejected
+	// due to unknown keys.
+	CheckUnknownKeys(diags *diag.Diagnostics, component diag.Component) bool
+}
+
+// FieldSourcer is implemented by parsers able to report the source key, for
+// example the environment variable, that set each field of the config
+// implementer, so that provenance can be recorded per field.
+type FieldSourcer interface {
+	// FieldSources returns the source key of each field set by the last
+	// parse, keyed by the dotted Go field path of the config implementer's
+	// field.
+	FieldSources() map[string]string
+}
+
+// KeyLocator is implemented by parsers able to locate the source of the keys
+// parsed into the config implementer.
+type KeyLocator interface {
+	// Locate returns the source range of the key parsed into the field at
+	// the dotted Go field path of the config implementer, for example
+	// "MyApp.Port", reporting whether the key was found.
+	Locate(field string) (diag.Range, bool)
+}
+
+// LocatingValidator is implemented by config implementers that validate with
+// the source range of the keys they reject. Parsers able to locate keys call
+// ValidateLocated instead of Validate.
+type LocatingValidator interface {
+	ValidateLocated(component diag.Component, locator KeyLocator) *diag.Diagnostics
+}
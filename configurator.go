@@ -1,31 +1,45 @@
 package configurator
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/matthewhartstonge/configurator/diag"
 )
 
-// New calls parse and returns merged config values in order of precedence:
+// New calls parse and returns merged config values, by default, in order of
+// precedence:
 //
 // 1. Command line.
-// 2. Config file that's name is declared on the command line.
-// 3. Environment variables
-// 3. Local Config File (if exists)
-// 3. Global Config File (if exists)
+// 2. Environment variables
+// 3. Config file that's name is declared on the command line.
+// 4. Local Config File (if exists)
+// 5. Global Config File (if exists)
 //
 // To be clear, this means config files are searched for and read first, then
 // environment variables are merged in over the top, then command line flags as
-// the highest priority.
-func New(config *Config) (*Config, *diag.Diagnostics) {
+// the highest priority. The order of precedence can be customised via
+// Options.Precedence.
+func New[T any](config *Config[T]) (*Config[T], *diag.Diagnostics) {
 	return config.Parse()
 }
 
-type Config struct {
+// Options provides the application level settings of a Config. Options are
+// passed through to each provider when parsing, which enables providers to
+// access settings, such as the application name, without needing to know the
+// type of the domain config.
+type Options struct {
 	// AppName defines the application name.
 	//
 	// For file based pathing, the application name is used to find the
@@ -39,16 +53,186 @@ type Config struct {
 	// FileFlag overrides the flag name used to process a config file at a
 	// specified place.
 	FileFlag string
+
+	// Profiles specifies the active profiles, for example, "prod". For each
+	// active profile, in order, `<FileName>.<profile>.<ext>` config files are
+	// merged over the base config file found by each file type parser.
+	// Profiles can also be selected, comma separated, at runtime via the
+	// ProfileEnv environment variable, or the ProfileFlag CLI flag, if
+	// enabled, which take precedence over the specified profiles.
+	Profiles []string
+	// ProfileFlag enables selecting the active profiles with the named CLI
+	// flag, for example "profile" to select profiles with `-profile`. The flag
+	// is removed from the arguments passed to flag configurators. If empty,
+	// profiles can't be selected via CLI flag.
+	ProfileFlag string
+	// ProfileEnv enables selecting the active profiles with the named
+	// environment variable, for example "APP_PROFILE". If empty, profiles
+	// can't be selected via environment variable.
+	ProfileEnv string
+
+	// Environment provides the environment variables and user directories
+	// used to discover and parse configuration. If nil, defaults to the
+	// process environment via OSEnvironment.
+	Environment Environment
+	// Layered enables processing every config file discovered for a
+	// component, merging each in order of precedence, so that, for example, a
+	// user config file is layered over the system config file. By default,
+	// only the first config file discovered is processed.
+	//
+	// Layered config files are parsed into the same config values, so values
+	// are reset before each layer is parsed.
+	Layered bool
+	// MinSeverity sets the minimum severity of diagnostics recorded while
+	// parsing, for example, diag.SeverityInfo discards debug and trace
+	// diagnostics. If unset, every diagnostic is recorded.
+	MinSeverity diag.Severity
+	// LogHandler streams each diagnostic to the handler live as Parse runs,
+	// see diag.LogDiagnostic.
+	LogHandler slog.Handler
+	// SearchPaths registers additional path strategies to search for config
+	// files, alongside the built-in global and local config paths. See
+	// Config.AddSearchPath.
+	SearchPaths []SearchPath
+	// DropInDir enables merging config fragments from a drop-in directory,
+	// for example "conf.d", found within each config directory. Fragments are
+	// merged in lexical order straight after the config file in the same
+	// directory, for example, /etc/app/conf.d/10-logging.yaml is merged over
+	// /etc/app/config.yaml, but under ~/.config/app/config.yaml. Unless
+	// Layered, only the drop-in directory alongside the config file processed
+	// is merged.
+	DropInDir string
+	// FS provides the filesystem that config files are discovered and read
+	// from. Discovered paths are resolved against the root of the FS, for
+	// example, /etc/app/config.yaml is read as etc/app/config.yaml. If nil,
+	// defaults to the OS filesystem.
+	FS fs.FS
+	// Args provides the command line arguments to process, including the
+	// program name. If nil, defaults to os.Args. Args are never mutated.
+	Args []string
+
+	// ConfigFilePaths stores the paths specified via the repeatable
+	// `-config-file` CLI flag. If non-empty, configurator will process each
+	// config file specified, in order, instead of attempting to find global or
+	// local config files. A path of "-" reads the config file from stdin.
+	ConfigFilePaths []string
+	// FileFormatFlag overrides the flag name used to specify the file type of
+	// specified config files that can't be determined by file extension, such
+	// as when reading from stdin. By default, the file type is specified with
+	// `-config-format`.
+	FileFormatFlag string
+	// FileFormat specifies the file type, for example "yaml", of config files
+	// that can't be determined by file extension. If empty, the file type is
+	// sniffed from the content of the config file.
+	FileFormat string
+	// Stdin provides the reader a config file path of "-" is read from. If
+	// nil, defaults to os.Stdin.
+	Stdin io.Reader
+
+	// Precedence specifies the order config sources are processed and merged
+	// in, from lowest to highest precedence, for example, to process
+	// environment variables over config files, but under CLI flags. If empty,
+	// defaults to DefaultPrecedence.
+	//
+	// A config file specified via the CLI replaces the global and local config
+	// files, taking their place if diag.ComponentFlagFile isn't included.
+	Precedence []diag.Component
+	// Disabled specifies config sources that should not be processed, for
+	// example, disabling global config files in containers.
+	Disabled []diag.Component
+
+	// profile stores the profile currently being processed, if any.
+	profile string
+	// flagArgs stores the arguments left for flag configurators to parse once
+	// the config file flag has been extracted.
+	flagArgs []string
+	// statted stores every config file path that was stat'ed during parsing,
+	// whether it was found or not, so that the paths can be watched.
+	statted []string
+	// stdin caches the config file read from stdin, as stdin can only be read
+	// once.
+	stdin     []byte
+	stdinRead bool
+}
+
+// FlagArgs returns the command line arguments, excluding the program name,
+// for flag configurators to parse. The config file flag is removed, as it is
+// processed by configurator itself.
+func (o *Options) FlagArgs() []string {
+	return o.flagArgs
+}
+
+// StatFile returns the file info for the named file from the configured
+// filesystem.
+func (o *Options) StatFile(name string) (fs.FileInfo, error) {
+	if name == StdinPath {
+		data, err := o.readStdin()
+		if err != nil {
+			return nil, err
+		}
+
+		return stdinFileInfo{size: int64(len(data))}, nil
+	}
+	if o.FS == nil {
+		return os.Stat(name)
+	}
+
+	return fs.Stat(o.FS, fsPath(name))
+}
+
+// ReadFile reads the named file from the configured filesystem.
+func (o *Options) ReadFile(name string) ([]byte, error) {
+	if name == StdinPath {
+		return o.readStdin()
+	}
+	if o.FS == nil {
+		return os.ReadFile(name)
+	}
+
+	return fs.ReadFile(o.FS, fsPath(name))
+}
+
+// readDir reads the named directory from the configured filesystem, returning
+// the directory entries sorted by filename.
+func (o *Options) readDir(name string) ([]fs.DirEntry, error) {
+	if o.FS == nil {
+		return os.ReadDir(name)
+	}
+
+	return fs.ReadDir(o.FS, fsPath(name))
+}
+
+// fsPath converts an OS file path into an unrooted, slash separated fs.FS
+// path.
+func fsPath(name string) string {
+	name = filepath.Clean(name)
+	name = strings.TrimPrefix(name, filepath.VolumeName(name))
+	name = strings.TrimLeft(filepath.ToSlash(name), "/")
+	if name == "" {
+		return "."
+	}
+
+	return name
+}
+
+// addStatted records a stat'ed config file path.
+func (o *Options) addStatted(path string) {
+	o.statted = append(o.statted, path)
+}
+
+type Config[T any] struct {
+	// Options provides the application level settings. While watching,
+	// reloads update the options concurrently, see Config.Watch.
+	Options
+
 	// Domain is your own domain specific config from which all other
 	// configuration types will be merged into. This struct can define its own
 	// specific types where each ConfigImplementer can implement the requisite
 	// type casting, validation and merging.
-	Domain any
+	// If nil, a zero valued T will be allocated on parse. While watching, read
+	// the domain config via Watcher.Domain instead, see Config.Watch.
+	Domain *T
 
-	// ConfigFilePath stores the path specified via the `-config` CLI flag.
-	// If non-empty, configurator will process the config file specified instead
-	// of attempting to find global or local config files.
-	ConfigFilePath string
 	// File provides a list of file configurators to parse, validate and merge
 	// global, current working directory and flag specified config files.
 	// Filetypes are processed and merged in specified order. This means that
@@ -62,282 +246,840 @@ type Config struct {
 	Flag ConfigFlagTypeable
 
 	// parsed stores the parsed values of each config.
-	parsed []ParsedConfig
+	parsed []ParsedConfig[T]
+	// provenance stores where each domain config field was set from, keyed
+	// by lower cased field path.
+	provenance map[string]*Provenance
+	// defaults stores a deep copy of the domain config as it was before the
+	// first parse, so that reloads start from the same defaults.
+	defaults *T
+	// flagInit reports if the flag configurator has been initialised.
+	flagInit bool
 }
 
 // ParsedConfig stores the parsed configuration values.
-type ParsedConfig struct {
+type ParsedConfig[T any] struct {
 	// Component specifies from where the config values came from.
 	Component diag.Component
 	// Path specifies either the file path, of environment variable prefix the
 	// values came from.
 	Path string
-	// Value holds the processed values.
+	// Value holds a deep copy of the processed values.
 	Value any
+	// Domain holds a deep copy of the domain config, taken once the processed
+	// values had been merged in.
+	Domain T
 }
 
 // Parse processes the
-func (c *Config) Parse() (*Config, *diag.Diagnostics) {
-	diags := new(diag.Diagnostics)
+func (c *Config[T]) Parse() (*Config[T], *diag.Diagnostics) {
+	diags := &diag.Diagnostics{MinSeverity: c.MinSeverity}
+	if c.LogHandler != nil {
+		diags.OnAppend(func(d diag.Diagnostic) {
+			_ = diag.LogDiagnostic(context.Background(), c.LogHandler, d)
+		})
+	}
 
 	// default filename to 'config' if not provided.
 	if c.FileName == "" {
 		c.FileName = DEFAULT_CONFIG_FILENAME
 	}
+	if c.Environment == nil {
+		c.Environment = OSEnvironment{}
+	}
+	if c.Domain == nil {
+		c.Domain = new(T)
+	}
+	if c.defaults == nil {
+		defaults := deepCopyOf(*c.Domain)
+		c.defaults = &defaults
+	}
 	c.parsed = nil
+	c.statted = nil
+	c.resetProvenance()
 
 	diags = c.processFileFlagConfig(diags)
+	diags = c.processProfiles(diags)
 
-	if c.ConfigFilePath != "" {
-		// Process OS application directory configuration files.
-		diags = c.processFileConfig(diags, diag.ComponentFlagFile)
-	} else {
-		// Process OS application directory configuration files.
-		diags = c.processFileConfig(diags, diag.ComponentGlobalFile)
+	pipeline, diags := c.pipeline(diags)
+	diags.Append(diag.Diagnostic{
+		Severity: diag.SeverityInfo,
+		Code:     CodePipeline,
+		Summary:  "Effective Config Pipeline",
+		Detail:   "Processing config sources from lowest to highest precedence: " + joinComponents(pipeline),
+	})
 
-		// Process current working directory configuration files.
-		diags = c.processFileConfig(diags, diag.ComponentLocalFile)
+	for _, component := range pipeline {
+		switch component {
+		case diag.ComponentGlobalFile, diag.ComponentLocalFile, diag.ComponentFlagFile:
+			// Process configuration files.
+			diags = c.processFileConfig(diags, component)
 
-		// Process environment variable configuration.
-		diags = c.processConfig(diags, diag.ComponentEnvVar, c.Env)
-	}
+		case diag.ComponentEnvVar:
+			// Process environment variable configuration.
+			diags = c.processConfig(diags, component, c.Env)
 
-	// Process CLI provided flag configuration.
-	diags = c.processFlagConfig(diags, diag.ComponentFlag)
+		case diag.ComponentFlag:
+			// Process CLI provided flag configuration.
+			diags = c.processFlagConfig(diags, component)
+
+		default:
+			diags.FromComponent(component, "").
+				Code(CodeUnknownSource).
+				Error("Unknown Config Source",
+					fmt.Sprintf("%s can't be processed as a config source, so has been skipped", component))
+		}
+	}
 
 	return c, diags
 }
 
+// DefaultPrecedence returns the default order config sources are processed and
+// merged in, from lowest to highest precedence.
+func DefaultPrecedence() []diag.Component {
+	return []diag.Component{
+		diag.ComponentGlobalFile,
+		diag.ComponentLocalFile,
+		diag.ComponentFlagFile,
+		diag.ComponentEnvVar,
+		diag.ComponentFlag,
+	}
+}
+
+// pipeline returns the effective order config sources are processed in, from
+// lowest to highest precedence, excluding disabled sources. If a config file
+// is specified, it replaces the global and local config files, taking their
+// place if the precedence doesn't place the specified config file itself.
+func (c *Config[T]) pipeline(diags *diag.Diagnostics) ([]diag.Component, *diag.Diagnostics) {
+	precedence := c.Precedence
+	if len(precedence) == 0 {
+		precedence = DefaultPrecedence()
+	}
+
+	specified := len(c.ConfigFilePaths) > 0
+	replaced := specified && !slices.Contains(c.Disabled, diag.ComponentFlagFile)
+
+	pipeline := make([]diag.Component, 0, len(precedence))
+	for _, component := range precedence {
+		switch {
+		case slices.Contains(c.Disabled, component):
+			continue
+		case component == diag.ComponentFlagFile && !specified:
+			continue
+		case (component == diag.ComponentGlobalFile || component == diag.ComponentLocalFile) && replaced:
+			if slices.Contains(precedence, diag.ComponentFlagFile) {
+				continue
+			}
+			component = diag.ComponentFlagFile
+		}
+
+		if !slices.Contains(pipeline, component) {
+			pipeline = append(pipeline, component)
+		}
+	}
+
+	if specified && !slices.Contains(pipeline, diag.ComponentFlagFile) {
+		reason := "the precedence doesn't include it, or the global and local config files it replaces"
+		if !replaced {
+			reason = "it has been disabled"
+		}
+
+		diags.FlagFile("-"+c.FileFlag).
+			Code(CodeSourceSkipped).
+			Warn("CLI Specified Config File Skipped",
+				fmt.Sprintf("The config file specified via -%s won't be processed, as %s", c.FileFlag, reason))
+	}
+
+	return pipeline, diags
+}
+
+// joinComponents returns a human-readable list of components.
+func joinComponents(components []diag.Component) string {
+	if len(components) == 0 {
+		return "none"
+	}
+
+	names := make([]string, len(components))
+	for i, component := range components {
+		names[i] = component.String()
+	}
+
+	return strings.Join(names, ", ")
+}
+
 // processFileFlagConfig extracts the path to a config file, if specified via
 // the customisable `-config-file` flag.
-func (c *Config) processFileFlagConfig(diags *diag.Diagnostics) *diag.Diagnostics {
+func (c *Config[T]) processFileFlagConfig(diags *diag.Diagnostics) *diag.Diagnostics {
 	if c.FileFlag == "" {
 		c.FileFlag = DEFAULT_CONFIG_FILEFLAG
 	}
+	if c.Args == nil {
+		c.Args = os.Args
+	}
 
 	// fully-qualified file flag.
 	fqFileFlag := "-" + c.FileFlag
 
-	// manually extract the value for the set config file flag.
-	v, ok := getFlagValue(c.FileFlag)
-	if !ok {
+	// flags are defined up front, so that the arguments are scanned as the
+	// flag configurator parses them.
+	c.initFlag()
+	isBool := c.boolFlags()
+
+	// flag configurators parse the arguments following the program name.
+	var args []string
+	if len(c.Args) > 0 {
+		args = c.Args[1:]
+	}
+	c.flagArgs = args
+
+	if c.FileFormatFlag == "" {
+		c.FileFormatFlag = DEFAULT_CONFIG_FORMATFLAG
+	}
+	if v, ok := getFlagValue(diags, diag.ComponentFlagFile, args, c.FileFormatFlag, isBool); ok {
+		c.FileFormat = v
+		diags.FlagFile("-"+c.FileFormatFlag).Code(CodeFileFormatSet).Trace("CLI specified config file format set", v)
+	}
+
+	// manually extract the values for the set config file flag.
+	values := getFlagValues(diags, diag.ComponentFlagFile, args, c.FileFlag, isBool)
+
+	// Remove the flags from the arguments passed to flag configurators.
+	c.flagArgs = removeFlagFromArgs(removeFlagFromArgs(args, c.FileFormatFlag, isBool), c.FileFlag, isBool)
+
+	if len(values) == 0 {
 		diags.FlagFile(fqFileFlag).
+			Code(CodeFileFlagUnset).
 			Trace("CLI specified config file path not set",
 				"Either the value was never set, or an empty string was provided")
 		return diags
 	}
 
-	c.ConfigFilePath = v
-	diags.FlagFile(fqFileFlag).Trace("CLI specified config file path added", c.ConfigFilePath)
-
-	// Remove the flag from os.Args
-	removeFlagFromArgs(c.FileFlag)
+	c.ConfigFilePaths = values
+	for _, v := range values {
+		diags.FlagFile(fqFileFlag).Code(CodeFileFlagSet).Trace("CLI specified config file path added", v)
+	}
 
 	return diags
 }
 
-// getFlagValue extracts the provided flag name from os.Args manually.
-func getFlagValue(name string) (string, bool) {
-	for i, arg := range os.Args {
-		if strings.HasPrefix(arg, "-"+name) {
-			if strings.Contains(arg, "=") {
-				// Handle -flag=value syntax
-				return strings.TrimSpace(strings.SplitN(arg, "=", 2)[1]), true
+// processFileConfig iterates through the provided file type parsers, stating the file.
+// If layered, every config file found is processed in order of precedence,
+// otherwise only the first config file found is processed. Drop-in config fragments are merged straight after the config
+// file of the directory they are found in.
+func (c *Config[T]) processFileConfig(diags *diag.Diagnostics, component diag.Component) *diag.Diagnostics {
+	paths, diags := getConfigPaths(diags, component, &c.Options)
+
+	// each CLI specified config file is always processed as its own layer.
+	layered := c.Layered || component == diag.ComponentFlagFile
+
+	for _, path := range paths {
+		found := false
+		for _, fileConfig := range c.File {
+			if path == StdinPath {
+				// stdin can only be parsed based on its content.
+				break
 			}
-			if i+1 < len(os.Args) {
-				// Handle -flag value syntax
-				return strings.TrimSpace(os.Args[i+1]), true
+			if !fileConfig.Stat(diags, component, &c.Options, path) {
+				// If we can't find the file, skip it.
+				continue
+			}
+
+			found = true
+			diags = c.processFileLayer(diags, component, path, fileConfig, layered)
+			diags = c.processProfileConfig(diags, component, path, fileConfig)
+			if !layered {
+				// process the first found config file based on file type priority.
+				diags, _ = c.processDropInConfig(diags, component, path)
+				return diags
 			}
 		}
+
+		// files without a known extension, such as rc files, are parsed based
+		// on their content.
+		if !found {
+			if fileConfig, ok := c.statSniffedFile(diags, component, path); ok {
+				found = true
+				diags = c.processFileLayer(diags, component, path, fileConfig, layered)
+				if !layered {
+					diags, _ = c.processDropInConfig(diags, component, path)
+					return diags
+				}
+			}
+		}
+
+		if !found && component == diag.ComponentFlagFile {
+			// the config file was explicitly asked for, so must exist.
+			if _, err := c.StatFile(path); err != nil {
+				diags.FlagFile(path).
+					Cause(fmt.Errorf("%w: %w", ErrConfigFileNotFound, err)).
+					Code(CodeFileNotFound).
+					Error("Config File Not Found",
+						"The CLI specified config file could not be found, error: "+err.Error())
+			}
+		}
+
+		if layered {
+			diags, _ = c.processDropInConfig(diags, component, path)
+		}
 	}
 
-	return "", false
+	if !layered {
+		// no config file was found, so merge the fragments of the first
+		// drop-in directory found instead.
+		for _, path := range paths {
+			var ok bool
+			if diags, ok = c.processDropInConfig(diags, component, path); ok {
+				break
+			}
+		}
+	}
+
+	return diags
 }
 
-// removeFlagFromArgs removes the flag and it's value from the global os.Args.
-func removeFlagFromArgs(name string) {
-	newArgs := make([]string, 0, len(os.Args))
+// processProfiles selects the active profiles from the profile environment
+// variable or CLI flag, if enabled and provided.
+func (c *Config[T]) processProfiles(diags *diag.Diagnostics) *diag.Diagnostics {
+	if c.ProfileEnv != "" {
+		if v, ok := c.Environment.LookupEnv(c.ProfileEnv); ok && v != "" {
+			c.Profiles = splitProfiles(v)
+			diags.Env(c.ProfileEnv).Code(CodeProfilesSelected).Trace("Profiles selected by environment variable", v)
+		}
+	}
 
-	for i := 0; i < len(os.Args); i++ {
-		arg := os.Args[i]
-		if strings.HasPrefix(arg, "-"+name) {
-			if strings.Contains(arg, "=") {
-				continue // remove `-flag=value` styled arg
-			}
-			i++ // remove `-flag value` style styled arg
+	if c.ProfileFlag != "" {
+		if v, ok := getFlagValue(diags, diag.ComponentFlag, c.flagArgs, c.ProfileFlag, c.boolFlags()); ok {
+			c.Profiles = splitProfiles(v)
+			diags.Flag("-"+c.ProfileFlag).Code(CodeProfilesSelected).Trace("Profiles selected by CLI flag", v)
 		}
-		newArgs = append(newArgs, arg)
+
+		// Remove the flag from the arguments passed to flag configurators.
+		c.flagArgs = removeFlagFromArgs(c.flagArgs, c.ProfileFlag, c.boolFlags())
+	}
+
+	if len(c.Profiles) > 0 {
+		diags.Append(diag.Diagnostic{
+			Severity: diag.SeverityInfo,
+			Code:     CodeProfilesActive,
+			Summary:  "Active Profiles",
+			Detail:   fmt.Sprintf("Profile config files will be merged for the %s profiles", strings.Join(c.Profiles, ", ")),
+		})
 	}
 
-	os.Args = newArgs
+	return diags
 }
 
-// processFileConfig iterates through the provided file type parsers, stating the file.
-func (c *Config) processFileConfig(diags *diag.Diagnostics, component diag.Component) *diag.Diagnostics {
-	paths, diags := getConfigPaths(diags, component, c)
+// splitProfiles splits a comma separated list of profiles.
+func splitProfiles(v string) []string {
+	var profiles []string
+	for _, profile := range strings.Split(v, ",") {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			profiles = append(profiles, profile)
+		}
+	}
 
-	for _, path := range paths {
-		for _, fileConfig := range c.File {
-			if !fileConfig.Stat(diags, component, c, path) {
-				// If we can't find the file, skip it.
-				continue
-			}
+	return profiles
+}
 
-			// process the first found config file based on file type priority.
-			return c.processConfig(diags, component, fileConfig)
+// processProfileConfig merges each active profile's overlay of the config file
+// found by the file type parser at the given path.
+func (c *Config[T]) processProfileConfig(diags *diag.Diagnostics, component diag.Component, path string, fileConfig ConfigFileTypeable) *diag.Diagnostics {
+	for _, profile := range c.Profiles {
+		c.profile = profile
+		found := fileConfig.Stat(diags, component, &c.Options, path)
+		c.profile = ""
+		if !found {
+			continue
 		}
+
+		resetFileValues(fileConfig)
+		diags.FromComponent(component, path).
+			Code(CodeProfileFileMerged).
+			Info("Merging Profile Config File",
+				fmt.Sprintf("Merging %s config file for the %q profile", fileConfig.Type(), profile))
+		diags = c.processConfig(diags, component, fileConfig)
 	}
 
 	return diags
 }
 
-// getConfigPaths returns file paths to the configuration directory.
-func getConfigPaths(diags *diag.Diagnostics, component diag.Component, cfg *Config) ([]string, *diag.Diagnostics) {
-	if pathStrategy, ok := configFilePathStrategies[component]; ok {
-		return pathStrategy(diags, cfg)
+// resetFileValues clears out the values left behind by the previously parsed
+// config file. Each layer, profile overlay and drop-in fragment is parsed into
+// the file config's same values, so without resetting, keys unset by the next
+// file would keep the previous file's values and be merged again.
+func resetFileValues(fileConfig ConfigFileTypeable) {
+	resetValues(fileConfig.Values())
+}
+
+// processFileLayer processes a found config file. If layered, the config values
+// are reset first, as each layer is parsed into the same config values.
+func (c *Config[T]) processFileLayer(diags *diag.Diagnostics, component diag.Component, path string, fileConfig ConfigFileTypeable, layered bool) *diag.Diagnostics {
+	if layered {
+		resetFileValues(fileConfig)
+		diags.FromComponent(component, path).
+			Code(CodeFileLayered).
+			Trace("Layering Config File",
+				fmt.Sprintf("Merging %s config file over any previous layers", fileConfig.Type()))
 	}
 
-	return nil, diags.
-		FromComponent(component, "").
-		Error("Unknown File Component Supplied",
-			fmt.Sprintf(
-				"File component %s was supplied, but required either a global or local file. "+
-					"This generally indicates a bug in the config parsing provider and should be reported as a bug",
-				component,
-			),
-		)
+	return c.processConfig(diags, component, fileConfig)
+}
+
+// statSniffedFile determines the file type of the file at the given path based
+// on its content, returning the first file type parser able to parse it.
+func (c *Config[T]) statSniffedFile(diags *diag.Diagnostics, component diag.Component, path string) (ConfigFileTypeable, bool) {
+	if info, err := c.StatFile(path); err != nil || info.IsDir() {
+		return nil, false
+	}
+
+	data, err := c.ReadFile(path)
+	if err != nil {
+		diags.FromComponent(component, path).
+			Cause(err).
+			Code(CodeFileUnreadable).
+			Trace("Unable to Read Config File",
+				"Unable to read the config file to determine its file type, error: "+err.Error())
+		return nil, false
+	}
+
+	fileType := c.FileFormat
+	if fileType == "" {
+		fileType = sniffFileType(data)
+	}
+	for _, fileConfig := range c.File {
+		stater, ok := fileConfig.(ConfigFileTypeStater)
+		if !ok {
+			continue
+		}
+
+		if stater.StatAs(diags, component, &c.Options, path, fileType) {
+			return fileConfig, true
+		}
+	}
+
+	diags.FromComponent(component, path).
+		Code(CodeFileTypeUnsupported).
+		Warn("Unsupported Config File Type",
+			fmt.Sprintf("The config file appears to be %s, but no config file parser supports it", fileType))
+	return nil, false
+}
+
+// processDropInConfig merges each config fragment found within the drop-in
+// directory of the config path, reporting whether the drop-in directory was
+// found. Fragments are merged in lexical order, using whichever file type
+// parser supports the fragment.
+func (c *Config[T]) processDropInConfig(diags *diag.Diagnostics, component diag.Component, path string) (*diag.Diagnostics, bool) {
+	if c.DropInDir == "" || path == StdinPath {
+		// stdin has no directory to find fragments in.
+		return diags, false
+	}
+
+	dir := filepath.Join(path, c.DropInDir)
+	// watch the directory itself to pick up added or removed fragments.
+	c.addStatted(dir)
+
+	entries, err := c.readDir(dir)
+	if err != nil {
+		diags.FromComponent(component, dir).
+			Cause(err).
+			Code(CodeDropInDirNotFound).
+			Trace("Drop-in Directory Not Found",
+				"No drop-in directory was found at the specified path, error: "+err.Error())
+		return diags, false
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		fragment := filepath.Join(dir, entry.Name())
+		diags = c.processDropInFragment(diags, component, fragment)
+	}
+
+	return diags, true
+}
+
+// processDropInFragment merges a drop-in config fragment with the first file
+// type parser that supports it.
+func (c *Config[T]) processDropInFragment(diags *diag.Diagnostics, component diag.Component, fragment string) *diag.Diagnostics {
+	for _, fileConfig := range c.File {
+		if !fileConfig.Stat(diags, component, &c.Options, fragment) {
+			continue
+		}
+
+		resetFileValues(fileConfig)
+		diags.FromComponent(component, fragment).
+			Code(CodeDropInFragmentMerged).
+			Info("Merging Drop-in Config Fragment",
+				fmt.Sprintf("Merging %s config fragment %s", fileConfig.Type(), filepath.Base(fragment)))
+
+		return c.processConfig(diags, component, fileConfig)
+	}
+
+	return diags.FromComponent(component, fragment).
+		Code(CodeDropInFragmentUnsupported).
+		Warn("Unsupported Drop-in Config Fragment",
+			fmt.Sprintf("No config file parser supports %s, so it has been skipped", filepath.Base(fragment)))
 }
 
-type configFilePathStrategy func(diags *diag.Diagnostics, cfg *Config) ([]string, *diag.Diagnostics)
+// getConfigPaths returns file paths to the configuration directory, searching
+// the built-in path strategy for the component along with any registered
+// search paths, in order.
+func getConfigPaths(diags *diag.Diagnostics, component diag.Component, opts *Options) ([]string, *diag.Diagnostics) {
+	pathStrategy, ok := configFilePathStrategies[component]
+	if !ok {
+		return nil, diags.
+			FromComponent(component, "").
+			Code(CodeUnknownComponent).
+			Error("Unknown File Component Supplied",
+				fmt.Sprintf(
+					"File component %s was supplied, but required either a global or local file. "+
+						"This generally indicates a bug in the config parsing provider and should be reported as a bug",
+					component,
+				),
+			)
+	}
+
+	searchPaths := []SearchPath{{
+		Name:      "Built-in " + component.String(),
+		Component: component,
+		Strategy:  pathStrategy,
+	}}
+	for _, searchPath := range opts.SearchPaths {
+		if searchPath.Component == component {
+			searchPaths = append(searchPaths, searchPath)
+		}
+	}
+	sort.SliceStable(searchPaths, func(i, j int) bool {
+		return searchPaths[i].Order < searchPaths[j].Order
+	})
+
+	var paths []string
+	for _, searchPath := range searchPaths {
+		if searchPath.Strategy == nil {
+			continue
+		}
+
+		diags.FromComponent(component, searchPath.Name).
+			Code(CodePathsSearched).
+			Trace("Searching Config Paths",
+				fmt.Sprintf("Searching %s config paths with order %d", searchPath.Name, searchPath.Order))
+
+		var found []string
+		found, diags = searchPath.Strategy(diags, component, opts)
+		paths = append(paths, found...)
+	}
+
+	return dedupePaths(paths), diags
+}
 
-var configFilePathStrategies = map[diag.Component]configFilePathStrategy{
+var configFilePathStrategies = map[diag.Component]PathStrategy{
 	diag.ComponentGlobalFile: processGlobalFilePaths,
 	diag.ComponentLocalFile:  processLocalFilePaths,
 	diag.ComponentFlagFile:   processFlagFilePath,
 }
 
-func processGlobalFilePaths(diags *diag.Diagnostics, cfg *Config) ([]string, *diag.Diagnostics) {
+// processGlobalFilePaths returns the system and user config directories, in
+// search order, following the XDG Base Directory specification:
+//
+// 1. /etc/{APP_NAME} (linux only)
+// 2. $XDG_CONFIG_DIRS/{APP_NAME}, for each directory, in the order listed.
+// 3. $XDG_CONFIG_HOME/{APP_NAME}, or the user config directory if unset.
+func processGlobalFilePaths(diags *diag.Diagnostics, _ diag.Component, opts *Options) ([]string, *diag.Diagnostics) {
 	var paths []string
 
 	if runtime.GOOS == "linux" {
 		// Search at /etc/{APP_NAME}
 		dir := string(filepath.Separator) + "etc"
-		fp := configFP(cfg, dir)
-		diags.GlobalFile(dir).Trace("User Configuration Directory Added", fp)
+		fp := configFP(opts, dir)
+		diags.GlobalFile(dir).Code(CodePathAdded).Trace("System Configuration Directory Added", fp)
 		paths = append(paths, fp)
 	}
 
-	if dir, err := os.UserConfigDir(); err != nil {
-		diags.GlobalFile(dir).Trace(
+	for _, dir := range xdgConfigDirs(diags, opts) {
+		fp := configFP(opts, dir)
+		diags.GlobalFile(dir).Code(CodePathAdded).Trace("XDG Configuration Directory Added", fp)
+		paths = append(paths, fp)
+	}
+
+	if dir, err := xdgConfigHome(diags, opts); err != nil {
+		diags.GlobalFile(dir).Cause(err).Code(CodePathUnavailable).Trace(
 			"Unable to Obtain Path to User Configuration Directory",
-			fmt.Sprintf("Unable to find path to global configuration '%s' file as %s", cfg.FileName, err.Error()),
+			fmt.Sprintf("Unable to find path to global configuration '%s' file as %s", opts.FileName, err.Error()),
 		)
 	} else {
-		fp := configFP(cfg, dir)
-		diags.GlobalFile(dir).Trace("User Configuration Directory Added", fp)
+		fp := configFP(opts, dir)
+		diags.GlobalFile(dir).Code(CodePathAdded).Trace("User Configuration Directory Added", fp)
 		paths = append(paths, fp)
 	}
 
 	return paths, diags
 }
 
-func processLocalFilePaths(diags *diag.Diagnostics, cfg *Config) ([]string, *diag.Diagnostics) {
+func processLocalFilePaths(diags *diag.Diagnostics, _ diag.Component, opts *Options) ([]string, *diag.Diagnostics) {
 	var paths []string
 
-	if dir, err := os.UserHomeDir(); err != nil {
-		diags.LocalFile(dir).Trace(
+	if dir, err := opts.Environment.UserHomeDir(); err != nil {
+		diags.LocalFile(dir).Cause(err).Code(CodePathUnavailable).Trace(
 			"Unable to obtain path to user home directory",
-			fmt.Sprintf("Unable to find path to local configuration '%s' file as %s", cfg.FileName, err.Error()),
+			fmt.Sprintf("Unable to find path to local configuration '%s' file as %s", opts.FileName, err.Error()),
 		)
 	} else {
-		fp := configFP(cfg, dir)
-		diags.LocalFile(dir).Trace("User home directory added", fp)
+		fp := configFP(opts, dir)
+		diags.LocalFile(dir).Code(CodePathAdded).Trace("User home directory added", fp)
 		paths = append(paths, fp)
 	}
 
-	if dir, err := os.Getwd(); err != nil {
-		diags.LocalFile(dir).Trace(
+	if dir, err := opts.Environment.Getwd(); err != nil {
+		diags.LocalFile(dir).Cause(err).Code(CodePathUnavailable).Trace(
 			"Unable to obtain path to current working directory",
-			fmt.Sprintf("Unable to find path to local configuration '%s' file as %s", cfg.FileName, err.Error()),
+			fmt.Sprintf("Unable to find path to local configuration '%s' file as %s", opts.FileName, err.Error()),
 		)
 	} else {
 		// check for a config file directly in the working directory.
-		diags.LocalFile(dir).Trace("Current working directory added", dir)
+		diags.LocalFile(dir).Code(CodePathAdded).Trace("Current working directory added", dir)
 		paths = append(paths, dir)
 	}
 
 	return paths, diags
 }
 
-func processFlagFilePath(diags *diag.Diagnostics, cfg *Config) ([]string, *diag.Diagnostics) {
-	if cfg.ConfigFilePath == "" {
-		return []string{}, diags
-	}
+func processFlagFilePath(diags *diag.Diagnostics, _ diag.Component, opts *Options) ([]string, *diag.Diagnostics) {
+	fqFileFlag := "-" + opts.FileFlag
 
-	fqFileFlag := "-" + cfg.FileFlag
+	paths := make([]string, 0, len(opts.ConfigFilePaths))
+	for _, fp := range opts.ConfigFilePaths {
+		if fp == StdinPath {
+			diags.FlagFile(fqFileFlag).Code(CodeFileFlagSet).Trace("CLI specified config file path added", "Reading config file from stdin")
+			paths = append(paths, fp)
+			continue
+		}
 
-	fp := cfg.ConfigFilePath
-	absFP, err := filepath.Abs(fp)
-	if err != nil {
-		diags.FlagFile(fqFileFlag).Error("Unable to compute the absolute file path", err.Error())
-		return []string{}, diags
+		absFP := filepath.Clean(fp)
+		if !filepath.IsAbs(absFP) {
+			// resolve relative paths against the environment's working directory.
+			wd, err := opts.Environment.Getwd()
+			if err != nil {
+				diags.FlagFile(fqFileFlag).Cause(err).Code(CodeFilePathInvalid).Error("Unable to compute the absolute file path", err.Error())
+				continue
+			}
+			absFP = filepath.Join(wd, absFP)
+		}
+
+		diags.FlagFile(fqFileFlag).Code(CodeFileFlagSet).Trace("CLI specified config file path added", absFP)
+		paths = append(paths, absFP)
 	}
 
-	diags.FlagFile(fqFileFlag).Trace("CLI specified config file path added", absFP)
-	return []string{absFP}, diags
+	return paths, diags
 }
 
 // configFP returns a well-formed path to an expected application directory.
-func configFP(cfg *Config, dir string) string {
-	return dir + string(filepath.Separator) + cfg.AppName
+func configFP(opts *Options, dir string) string {
+	return dir + string(filepath.Separator) + opts.AppName
 }
 
 // processFlagConfig processes and merges in any provided flag configuration.
-func (c *Config) processFlagConfig(diags *diag.Diagnostics, component diag.Component) *diag.Diagnostics {
+func (c *Config[T]) processFlagConfig(diags *diag.Diagnostics, component diag.Component) *diag.Diagnostics {
 	if c.Flag == nil {
 		return diags
 	}
 
-	c.Flag.Init()
+	c.initFlag()
 
 	return c.processConfig(diags, component, c.Flag)
 }
 
+// initFlag initialises the flag configurator, once, as flags can only be
+// defined once.
+func (c *Config[T]) initFlag() {
+	if c.Flag == nil || c.flagInit {
+		return
+	}
+
+	c.Flag.Init()
+	c.flagInit = true
+}
+
+// boolFlags returns the flag configurator's report of which of its flags are
+// boolean, or nil if the flag configurator can't report them.
+func (c *Config[T]) boolFlags() func(name string) bool {
+	if reporter, ok := c.Flag.(BoolFlagReporter); ok {
+		return reporter.IsBoolFlag
+	}
+
+	return nil
+}
+
 // processConfig does the heavy lifting of parsing, validating and merging the
 // config together returning diagnostic information at the end of the process.
-func (c *Config) processConfig(diags *diag.Diagnostics, component diag.Component, configurer ConfigTypeable) *diag.Diagnostics {
+func (c *Config[T]) processConfig(diags *diag.Diagnostics, component diag.Component, configurer ConfigTypeable) *diag.Diagnostics {
 	if configurer == nil {
 		// no parser provided, may be expected, for example, if CLI flags aren't implemented.
 		diags.FromComponent(component, "").
+			Code(CodeNoConfigurator).
 			Trace("No configurator provided",
 				fmt.Sprintf("Error attempting to parse %s configuration", component))
 		return diags
 	}
 
-	path, err := configurer.Parse(c)
+	path, err := configurer.Parse(&c.Options)
 	if err != nil {
 		// Low-level parsing issue
-		diags.FromComponent(component, configurer.Type()).
-			Error(fmt.Sprintf("Error parsing %s configuration", component),
-				err.Error())
+		return c.processParseError(diags, component, configurer, path, err)
+	}
+
+	if checker, ok := configurer.(UnknownKeyChecker); ok && !checker.CheckUnknownKeys(diags, component) {
+		// the config file has been rejected.
 		return diags
 	}
 
+	diags = c.processValidation(diags, configurer.Validate(component))
+
+	before := flattenFields(c.Domain)
+	diags = c.merge(diags, component, configurer)
+	c.recordProvenance(component, path, before, c.fieldSources(configurer))
+
 	c.appendParsedConfig(component, path, configurer.Values())
 
-	diags.Merge(configurer.Validate(component))
+	return diags
+}
+
+// processValidation merges the diagnostics reported by validation, marking
+// each fatal or error diagnostic as caused by ErrValidationFailed, if not
+// caused by a more specific error, and coded CodeValidationFailed, if not
+// given a more specific code.
+func (c *Config[T]) processValidation(diags *diag.Diagnostics, validation *diag.Diagnostics) *diag.Diagnostics {
+	for d := range validation.Seq() {
+		if d.Severity == diag.SeverityFatal || d.Severity == diag.SeverityError {
+			if d.Code == "" {
+				d.Code = CodeValidationFailed
+			}
+			if d.Cause == nil {
+				d.Cause = ErrValidationFailed
+			} else if !errors.Is(d.Cause, ErrValidationFailed) {
+				d.Cause = fmt.Errorf("%w: %w", ErrValidationFailed, d.Cause)
+			}
+		}
 
-	c.Domain = configurer.Merge(c.Domain)
+		diags.Append(d)
+	}
 
 	return diags
 }
 
+// processParseError reports the error returned from parsing, reporting each
+// source error located within the config file as its own diagnostic.
+func (c *Config[T]) processParseError(diags *diag.Diagnostics, component diag.Component, configurer ConfigTypeable, path string, err error) *diag.Diagnostics {
+	summary := fmt.Sprintf("Error parsing %s configuration", component)
+
+	srcErrs := sourceErrors(err)
+	if len(srcErrs) == 0 {
+		return diags.FromComponent(component, configurer.Type()).
+			Cause(fmt.Errorf("%w: %w", ErrParseFailed, err)).
+			Code(CodeParseFailed).
+			Error(summary, err.Error())
+	}
+
+	for _, srcErr := range srcErrs {
+		rng := srcErr.Range
+		if rng.Filename == "" {
+			rng.Filename = path
+		}
+
+		diags.FromComponent(component, configurer.Type()).
+			At(rng).
+			Cause(fmt.Errorf("%w: %w", ErrParseFailed, srcErr)).
+			Code(CodeParseFailed).
+			Error(summary, srcErr.Err.Error())
+	}
+
+	return diags
+}
+
+// merge binds the parsed configuration values into the domain config. Typed
+// implementers are merged directly, implementers without a Merge method are
+// merged reflectively, otherwise the configurer is adapted as an untyped
+// ConfigMerger.
+func (c *Config[T]) merge(diags *diag.Diagnostics, component diag.Component, configurer ConfigTypeable) *diag.Diagnostics {
+	values := configurer.Values()
+	if merger, ok := values.(DomainMerger[T]); ok {
+		merger.Merge(c.Domain)
+		return diags
+	}
+
+	if _, ok := values.(ConfigMerger); !ok && values != nil {
+		if !hasMergeTags(reflect.TypeOf(values)) {
+			return c.reportNothingMerged(diags, component, configurer, values)
+		}
+
+		if err := Merge(c.Domain, values); err != nil {
+			diags.FromComponent(component, configurer.Type()).
+				Cause(err).
+				Code(CodeMergeFailed).
+				Error(fmt.Sprintf("Error merging %s configuration", component),
+					err.Error())
+		}
+		return diags
+	}
+
+	var merged any
+	switch merger := configurer.(type) {
+	case ConfigErrMerger:
+		var err error
+		if merged, err = merger.MergeE(c.Domain); err != nil {
+			diags.FromComponent(component, configurer.Type()).
+				Cause(err).
+				Code(CodeMergeFailed).
+				Error(fmt.Sprintf("Error merging %s configuration", component),
+					err.Error())
+			return diags
+		}
+	case ConfigMerger:
+		merged = merger.Merge(c.Domain)
+	default:
+		diags.FromComponent(component, configurer.Type()).
+			Code(CodeNoMerger).
+			Trace("No merger provided",
+				fmt.Sprintf("Skipping merging %s configuration", component))
+		return diags
+	}
+
+	domain, ok := merged.(*T)
+	if !ok || domain == nil {
+		diags.FromComponent(component, configurer.Type()).
+			Code(CodeMergeFailed).
+			Error(fmt.Sprintf("Error merging %s configuration", component),
+				fmt.Sprintf("Merge must return the domain config as %T", c.Domain))
+		return diags
+	}
+
+	c.Domain = domain
+	return diags
+}
+
+// reportNothingMerged reports config values that can't be merged, as the
+// values neither implement a recognised Merge method, nor tag any fields to be
+// merged reflectively.
+func (c *Config[T]) reportNothingMerged(diags *diag.Diagnostics, component diag.Component, configurer ConfigTypeable, values any) *diag.Diagnostics {
+	detail := fmt.Sprintf("%T doesn't implement Merge(%T), or tag any fields with `%s`, so none of its values have been merged", values, c.Domain, MergeTag)
+	if _, ok := reflect.TypeOf(values).MethodByName("Merge"); ok {
+		detail = fmt.Sprintf("%T has a Merge method, but it doesn't match Merge(%T), and no fields are tagged with `%s`, so none of its values have been merged", values, c.Domain, MergeTag)
+	}
+
+	return diags.FromComponent(component, configurer.Type()).
+		Code(CodeNothingMerged).
+		Warn(fmt.Sprintf("Nothing merged from %s configuration", component), detail)
+}
+
 // appendParsedConfig injects parsed config values for later perusal.
-func (c *Config) appendParsedConfig(component diag.Component, path string, v any) {
-	c.parsed = append(c.parsed, ParsedConfig{component, path, v})
+func (c *Config[T]) appendParsedConfig(component diag.Component, path string, v any) {
+	c.parsed = append(c.parsed, ParsedConfig[T]{component, path, copyValues(v), deepCopyOf(*c.Domain)})
 }
 
 // Values returns the evaluated configuration values.
-func (c *Config) Values() []ParsedConfig {
+//
+// Values isn't safe to call while watching, use Watcher.Values instead.
+func (c *Config[T]) Values() []ParsedConfig[T] {
 	return c.parsed
 }
@@ -1,6 +1,7 @@
 package configurator
 
 const (
-	DEFAULT_CONFIG_FILENAME = "config"
-	DEFAULT_CONFIG_FILEFLAG = "config-file"
+	DEFAULT_CONFIG_FILENAME   = "config"
+	DEFAULT_CONFIG_FILEFLAG   = "config-file"
+	DEFAULT_CONFIG_FORMATFLAG = "config-format"
 )
@@ -8,6 +8,32 @@ type Builder struct {
 	e *Diagnostic
 }
 
+// At locates the source that the diagnostic refers to, for example, the
+// position of a config file key that failed validation.
+func (b *Builder) At(rng Range) *Builder {
+	b.e.Range = &rng
+	return b
+}
+
+// Code identifies the kind of diagnostic with a machine-stable code.
+func (b *Builder) Code(code Code) *Builder {
+	b.e.Code = code
+	return b
+}
+
+// Suggest attaches a remediation hint to the diagnostic, overriding the
+// suggestion registered for the diagnostic's code.
+func (b *Builder) Suggest(suggestion string) *Builder {
+	b.e.Suggestion = suggestion
+	return b
+}
+
+// Cause preserves the underlying error that caused the diagnostic.
+func (b *Builder) Cause(err error) *Builder {
+	b.e.Cause = err
+	return b
+}
+
 func (b *Builder) Fatal(summary, detail string) *Diagnostics {
 	return b.build(SeverityFatal, summary, detail)
 }
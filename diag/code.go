@@ -0,0 +1,93 @@
+package diag
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// Code is a machine-stable identifier for a kind of diagnostic, for example
+// "CFG1001", enabling operators and support runbooks to reliably match
+// diagnostics, regardless of changes to their summary.
+type Code string
+
+// String returns the code along with its registered name, if registered, for
+// example "CFG1001 file-not-found".
+func (c Code) String() string {
+	if info, ok := LookupCode(c); ok && info.Name != "" {
+		return string(c) + " " + info.Name
+	}
+
+	return string(c)
+}
+
+// CodeInfo describes a registered diagnostic code.
+type CodeInfo struct {
+	// Code is the machine-stable code.
+	Code Code
+	// Name is a short, human-readable, kebab-cased name for the code, for
+	// example "file-not-found".
+	Name string
+	// Description describes the condition the code is reported for.
+	Description string
+	// Suggestion is the default remediation hint attached to diagnostics
+	// reported with the code that don't provide their own suggestion.
+	Suggestion string
+}
+
+// codes is the registry of diagnostic codes.
+var codes = struct {
+	sync.RWMutex
+	m map[Code]CodeInfo
+}{m: make(map[Code]CodeInfo)}
+
+// RegisterCode registers a diagnostic code, enabling library users to extend
+// the codes reported by configurator with codes for their own Validate
+// methods. Codes must be unique, so it is recommended to prefix codes with an
+// application specific prefix, as configurator reserves the "CFG" prefix.
+func RegisterCode(info CodeInfo) error {
+	if info.Code == "" {
+		return fmt.Errorf("diagnostic code must be provided for %q", info.Name)
+	}
+
+	codes.Lock()
+	defer codes.Unlock()
+
+	if _, ok := codes.m[info.Code]; ok {
+		return fmt.Errorf("diagnostic code %s has already been registered", info.Code)
+	}
+	codes.m[info.Code] = info
+
+	return nil
+}
+
+// MustRegisterCode registers each of the diagnostic codes, panicking if a code
+// can't be registered.
+func MustRegisterCode(infos ...CodeInfo) {
+	for _, info := range infos {
+		if err := RegisterCode(info); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// LookupCode returns the registered information for the code.
+func LookupCode(code Code) (CodeInfo, bool) {
+	codes.RLock()
+	defer codes.RUnlock()
+
+	info, ok := codes.m[code]
+	return info, ok
+}
+
+// Codes returns every registered diagnostic code, sorted by code.
+func Codes() []CodeInfo {
+	codes.RLock()
+	defer codes.RUnlock()
+
+	return slices.SortedFunc(maps.Values(codes.m), func(a, b CodeInfo) int {
+		return cmp.Compare(a.Code, b.Code)
+	})
+}
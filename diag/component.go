@@ -20,6 +20,9 @@ const (
 	// ComponentFlagFile states that the diagnostic comes from a CLI specified
 	// config file.
 	ComponentFlagFile
+	// ComponentDefault states that the diagnostic comes from the default values
+	// of the domain config.
+	ComponentDefault
 )
 
 func (c Component) String() string {
@@ -34,6 +37,8 @@ func (c Component) String() string {
 		return "CLI Flag"
 	case ComponentFlagFile:
 		return "CLI Specified Config File"
+	case ComponentDefault:
+		return "Default Value"
 	default:
 		return "Invalid"
 	}
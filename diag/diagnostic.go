@@ -13,13 +13,28 @@ type Diagnostic struct {
 	Path      string
 	Summary   string
 	Detail    string
+	// Code optionally provides a machine-stable identifier for the kind of
+	// diagnostic, see RegisterCode.
+	Code Code
+	// Suggestion optionally provides a remediation hint. If not provided, the
+	// suggestion registered for the code is used.
+	Suggestion string
+	// Range optionally locates the source the diagnostic refers to.
+	Range *Range
+	// Cause optionally preserves the underlying error that caused the
+	// diagnostic, enabling errors.Is and errors.As to inspect it.
+	Cause error
 }
 
 // Error implements error.
 func (d Diagnostic) Error() string {
 	var buf strings.Builder
 
-	_, _ = fmt.Fprintf(&buf, "%s:", strings.ToUpper(d.Severity.String()))
+	_, _ = fmt.Fprintf(&buf, "%s", strings.ToUpper(d.Severity.String()))
+	if d.Code != "" {
+		_, _ = fmt.Fprintf(&buf, "[%s]", d.Code)
+	}
+	_, _ = fmt.Fprint(&buf, ":")
 
 	if component := d.Component.String(); componentInvalid.String() != component {
 		_, _ = fmt.Fprintf(&buf, " (%s)", component)
@@ -29,12 +44,24 @@ func (d Diagnostic) Error() string {
 		_, _ = fmt.Fprintf(&buf, " [%s]", d.Path)
 	}
 
+	if d.Range != nil {
+		_, _ = fmt.Fprintf(&buf, " at %s", d.Range)
+	}
+
 	_, _ = fmt.Fprintf(&buf, " Summary: \"%s\"", d.Summary)
 	if d.Detail != "" {
 		_, _ = fmt.Fprintf(&buf, " Detail: \"%s\"", d.Detail)
 	}
+	if d.Suggestion != "" {
+		_, _ = fmt.Fprintf(&buf, " Suggestion: \"%s\"", d.Suggestion)
+	}
 
 	_, _ = fmt.Fprintln(&buf)
 
 	return buf.String()
 }
+
+// Unwrap returns the underlying error that caused the diagnostic.
+func (d Diagnostic) Unwrap() error {
+	return d.Cause
+}
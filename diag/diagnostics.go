@@ -11,6 +11,23 @@ type Diagnostics struct {
 	HasError bool
 	// HasWarn reports if the diagnostics have reported a warning.
 	HasWarn bool
+
+	// MinSeverity sets the minimum severity of diagnostic entries recorded,
+	// for example, SeverityInfo discards debug and trace entries as they are
+	// appended. If unset, every diagnostic entry is recorded. HasFatal,
+	// HasError and HasWarn are set even if the entry is discarded, so that Err
+	// still reports the failure.
+	MinSeverity Severity
+
+	// listeners are notified of each diagnostic entry as it is appended.
+	listeners []func(Diagnostic)
+}
+
+// OnAppend registers a listener to be notified of each diagnostic entry as it
+// is appended, enabling diagnostics to be streamed live, for example to a
+// slog.Handler, rather than inspected after the fact.
+func (d *Diagnostics) OnAppend(fn func(Diagnostic)) {
+	d.listeners = append(d.listeners, fn)
 }
 
 // Len reports the number of diagnostic messages logged.
@@ -48,28 +65,37 @@ func (d *Diagnostics) Append(diags ...Diagnostic) {
 			d.HasWarn = true
 		}
 
+		if d.MinSeverity != severityInvalid && diag.Severity > d.MinSeverity {
+			// Below the minimum recorded severity.
+			continue
+		}
+
+		if diag.Suggestion == "" && diag.Code != "" {
+			if info, ok := LookupCode(diag.Code); ok {
+				diag.Suggestion = info.Suggestion
+			}
+		}
+
 		d.diags = append(d.diags, diag)
+		for _, fn := range d.listeners {
+			fn(diag)
+		}
 	}
 }
 
-// Merge appends the provided diags into the diagnostics.
+// Merge appends the provided diags into the diagnostics, along with the
+// severities they have reported.
 func (d *Diagnostics) Merge(diags *Diagnostics) {
-	if diags.Len() == 0 {
-		// Nothing to append!
+	if diags == nil {
 		return
 	}
 
-	if diags.HasFatal {
-		d.HasFatal = diags.HasFatal
-	}
-	if diags.HasError {
-		d.HasError = diags.HasError
-	}
-	if diags.HasWarn {
-		d.HasWarn = diags.HasWarn
-	}
+	// entries may have been discarded by the provided diags' MinSeverity.
+	d.HasFatal = d.HasFatal || diags.HasFatal
+	d.HasError = d.HasError || diags.HasError
+	d.HasWarn = d.HasWarn || diags.HasWarn
 
-	d.diags = append(d.diags, diags.All()...)
+	d.Append(diags.All()...)
 }
 
 // GlobalFile enables building up a diagnostic message for a global
@@ -150,14 +176,7 @@ func (d *Diagnostics) Traces() *Diagnostics {
 // getDiagsWithLevel returns an array of diagnostics that match the specified
 // severity level.
 func (d *Diagnostics) getDiagsWithLevel(sev Severity) *Diagnostics {
-	diags := &Diagnostics{}
-	for _, diag := range d.diags {
-		if diag.Severity != sev {
-			continue
-		}
-
-		diags.Append(diag)
-	}
-
-	return diags
+	return d.Filter(func(diag Diagnostic) bool {
+		return diag.Severity == sev
+	})
 }
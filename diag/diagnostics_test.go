@@ -0,0 +1,75 @@
+package diag
+
+import "testing"
+
+func TestAppendMinSeverity(t *testing.T) {
+	tests := []struct {
+		name        string
+		minSeverity Severity
+		severity    Severity
+		wantLen     int
+		wantErr     bool
+		wantWarn    bool
+	}{
+		{
+			name:     "unset records everything",
+			severity: SeverityTrace,
+			wantLen:  1,
+		},
+		{
+			name:        "discards entries below the minimum",
+			minSeverity: SeverityInfo,
+			severity:    SeverityDebug,
+		},
+		{
+			name:        "records entries at the minimum",
+			minSeverity: SeverityWarn,
+			severity:    SeverityWarn,
+			wantLen:     1,
+			wantWarn:    true,
+		},
+		{
+			name:        "discarded warnings are flagged",
+			minSeverity: SeverityError,
+			severity:    SeverityWarn,
+			wantWarn:    true,
+		},
+		{
+			name:        "discarded errors still fail",
+			minSeverity: SeverityFatal,
+			severity:    SeverityError,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := &Diagnostics{MinSeverity: tt.minSeverity}
+			diags.Append(Diagnostic{Severity: tt.severity, Summary: "summary"})
+
+			if got := diags.Len(); got != tt.wantLen {
+				t.Errorf("Len() = %d, want %d", got, tt.wantLen)
+			}
+			if diags.HasError != tt.wantErr {
+				t.Errorf("HasError = %v, want %v", diags.HasError, tt.wantErr)
+			}
+			if diags.HasWarn != tt.wantWarn {
+				t.Errorf("HasWarn = %v, want %v", diags.HasWarn, tt.wantWarn)
+			}
+			if err := diags.Err(); (err != nil) != tt.wantErr {
+				t.Errorf("Err() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMergeDiscardedErrors(t *testing.T) {
+	discarded := &Diagnostics{MinSeverity: SeverityFatal}
+	discarded.Append(Diagnostic{Severity: SeverityError, Summary: "summary"})
+
+	diags := new(Diagnostics)
+	diags.Merge(discarded)
+	if !diags.HasError || diags.Err() == nil {
+		t.Errorf("Merge() lost the discarded error, HasError = %v, Err() = %v", diags.HasError, diags.Err())
+	}
+}
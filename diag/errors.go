@@ -0,0 +1,49 @@
+package diag
+
+import (
+	"strings"
+)
+
+// Err returns the fatal and error diagnostic entries as an error, or nil if
+// none have been reported. Errors are reported even if their entries were
+// discarded by MinSeverity. The returned error unwraps to each diagnostic
+// entry, and in turn each entry's cause, so errors.Is and errors.As can be
+// used to inspect the underlying errors.
+func (d *Diagnostics) Err() error {
+	if d == nil || (!d.HasFatal && !d.HasError) {
+		return nil
+	}
+
+	return &diagnosticsError{
+		diags: d.Filter(AtOrAbove(SeverityError)).All(),
+	}
+}
+
+// diagnosticsError reports fatal and error diagnostic entries as an error.
+type diagnosticsError struct {
+	diags []Diagnostic
+}
+
+// Error implements error.
+func (e *diagnosticsError) Error() string {
+	if len(e.diags) == 0 {
+		return "errors were reported below the minimum recorded severity"
+	}
+
+	msgs := make([]string, 0, len(e.diags))
+	for _, diag := range e.diags {
+		msgs = append(msgs, strings.TrimSuffix(diag.Error(), "\n"))
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns each of the diagnostic entries.
+func (e *diagnosticsError) Unwrap() []error {
+	errs := make([]error, 0, len(e.diags))
+	for _, diag := range e.diags {
+		errs = append(errs, diag)
+	}
+
+	return errs
+}
@@ -0,0 +1,136 @@
+package diag
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"strings"
+)
+
+// Filter reports whether a diagnostic entry should be kept.
+type Filter func(diag Diagnostic) bool
+
+// ByComponent keeps diagnostic entries reported by any of the components.
+func ByComponent(components ...Component) Filter {
+	return func(diag Diagnostic) bool {
+		return slices.Contains(components, diag.Component)
+	}
+}
+
+// ByPathPrefix keeps diagnostic entries with a path starting with the prefix.
+func ByPathPrefix(prefix string) Filter {
+	return func(diag Diagnostic) bool {
+		return strings.HasPrefix(diag.Path, prefix)
+	}
+}
+
+// AtOrAbove keeps diagnostic entries at the severity or more severe, for
+// example, AtOrAbove(SeverityWarn) keeps fatal, error and warning entries.
+func AtOrAbove(sev Severity) Filter {
+	return func(diag Diagnostic) bool {
+		return diag.Severity != severityInvalid && diag.Severity <= sev
+	}
+}
+
+// Not keeps diagnostic entries the filter discards.
+func Not(filter Filter) Filter {
+	return func(diag Diagnostic) bool {
+		return !filter(diag)
+	}
+}
+
+// AnyOf keeps diagnostic entries kept by any of the filters.
+func AnyOf(filters ...Filter) Filter {
+	return func(diag Diagnostic) bool {
+		for _, filter := range filters {
+			if filter(diag) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Filter returns the diagnostic entries kept by every filter.
+func (d *Diagnostics) Filter(filters ...Filter) *Diagnostics {
+	diags := &Diagnostics{}
+	for diag := range d.Seq() {
+		if !slices.ContainsFunc(filters, func(filter Filter) bool { return !filter(diag) }) {
+			diags.Append(diag)
+		}
+	}
+
+	return diags
+}
+
+// Seq returns an iterator over the diagnostic entries.
+func (d *Diagnostics) Seq() iter.Seq[Diagnostic] {
+	return func(yield func(Diagnostic) bool) {
+		for _, diag := range d.All() {
+			if !yield(diag) {
+				return
+			}
+		}
+	}
+}
+
+// Sorted returns the diagnostic entries stably sorted by the comparison
+// functions, with later comparisons breaking ties of earlier comparisons, for
+// example:
+//
+//	diags.Sorted(diag.CompareSeverity, diag.ComparePath)
+func (d *Diagnostics) Sorted(cmps ...func(a, b Diagnostic) int) *Diagnostics {
+	sorted := slices.Clone(d.All())
+	slices.SortStableFunc(sorted, func(a, b Diagnostic) int {
+		for _, cmp := range cmps {
+			if c := cmp(a, b); c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	})
+
+	diags := &Diagnostics{}
+	diags.Append(sorted...)
+
+	return diags
+}
+
+// CompareSeverity orders diagnostic entries from most to least severe.
+func CompareSeverity(a, b Diagnostic) int {
+	return cmp.Compare(a.Severity, b.Severity)
+}
+
+// CompareComponent orders diagnostic entries by component.
+func CompareComponent(a, b Diagnostic) int {
+	return cmp.Compare(a.Component, b.Component)
+}
+
+// ComparePath orders diagnostic entries by path.
+func ComparePath(a, b Diagnostic) int {
+	return cmp.Compare(a.Path, b.Path)
+}
+
+// GroupByComponent groups the diagnostic entries by component.
+func (d *Diagnostics) GroupByComponent() map[Component]*Diagnostics {
+	return GroupBy(d, func(diag Diagnostic) Component {
+		return diag.Component
+	})
+}
+
+// GroupBy groups the diagnostic entries by the key returned for each entry,
+// keeping the order of entries within each group.
+func GroupBy[K comparable](d *Diagnostics, key func(diag Diagnostic) K) map[K]*Diagnostics {
+	groups := make(map[K]*Diagnostics)
+	for diag := range d.Seq() {
+		k := key(diag)
+		if groups[k] == nil {
+			groups[k] = &Diagnostics{}
+		}
+		groups[k].Append(diag)
+	}
+
+	return groups
+}
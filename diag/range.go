@@ -0,0 +1,56 @@
+package diag
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Pos describes a position within a source file. Lines and columns are
+// counted from 1, where a column of 0 states the column is unknown.
+type Pos struct {
+	Line   int
+	Column int
+}
+
+// Range describes a range of source within a file that a diagnostic refers to.
+type Range struct {
+	// Filename is the name of the source file.
+	Filename string
+	// Start is the position of the first character of the range.
+	Start Pos
+	// End is the position of the last character of the range, which may be
+	// equal to Start if the range is a single position.
+	End Pos
+}
+
+// String returns the range in the conventional `file:line:column` form.
+func (r Range) String() string {
+	s := r.Filename
+	if r.Start.Line > 0 {
+		if s != "" {
+			s += ":"
+		}
+		s += fmt.Sprint(r.Start.Line)
+		if r.Start.Column > 0 {
+			s += fmt.Sprintf(":%d", r.Start.Column)
+		}
+	}
+
+	return s
+}
+
+// OffsetPos converts a byte offset within the source into a line and column
+// position, for parsers that only report errors at a byte offset.
+func OffsetPos(src []byte, offset int64) Pos {
+	offset = max(0, min(offset, int64(len(src))))
+
+	before := src[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+
+	return Pos{
+		Line:   line,
+		Column: utf8.RuneCount(before[lineStart:]) + 1,
+	}
+}
@@ -0,0 +1,44 @@
+package diag
+
+import (
+	"io"
+	"os"
+)
+
+var (
+	_ Renderer = (*TextRenderer)(nil)
+	_ Renderer = (*SnippetRenderer)(nil)
+	_ Renderer = (*JSONRenderer)(nil)
+	_ Renderer = (*SARIFRenderer)(nil)
+	_ Renderer = (*GitHubRenderer)(nil)
+)
+
+// Renderer renders diagnostics to an output format, enabling the same
+// diagnostics to drive both operator output and CI checks.
+type Renderer interface {
+	// Render writes the diagnostics to the writer.
+	Render(w io.Writer, diags *Diagnostics) error
+}
+
+// NewTerminalRenderer returns a text renderer for the file, rendering ANSI
+// colored output if the file is a terminal, unless disabled by setting the
+// NO_COLOR environment variable.
+func NewTerminalRenderer(f *os.File) *TextRenderer {
+	return &TextRenderer{
+		Color: isTerminal(f) && os.Getenv("NO_COLOR") == "",
+	}
+}
+
+// isTerminal reports whether the file is a terminal.
+func isTerminal(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,92 @@
+package diag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GitHubRenderer renders diagnostics as GitHub Actions workflow commands, so
+// that diagnostics are annotated against the offending config files, for
+// example:
+//
+//	::error file=config.yaml,line=2,col=1,title=CFG1011 Unknown Config Key::The key "prot" is not supported by the config
+//
+// Debug and trace diagnostics are rendered as debug messages, which are only
+// shown when step debug logging is enabled.
+type GitHubRenderer struct{}
+
+// Render implements Renderer.
+func (r *GitHubRenderer) Render(w io.Writer, diags *Diagnostics) error {
+	var buf bytes.Buffer
+	for _, diag := range diags.All() {
+		command, props := githubCommand(diag.Severity), []string(nil)
+
+		if rng := diag.Range; rng != nil && command != "debug" {
+			if rng.Filename != "" {
+				props = append(props, "file="+githubEscapeProperty(rng.Filename))
+			}
+			if rng.Start.Line > 0 {
+				props = append(props, fmt.Sprintf("line=%d", rng.Start.Line))
+			}
+			if rng.Start.Column > 0 {
+				props = append(props, fmt.Sprintf("col=%d", rng.Start.Column))
+			}
+			if rng.End.Line > 0 {
+				props = append(props, fmt.Sprintf("endLine=%d", rng.End.Line))
+			}
+			if rng.End.Column > 0 {
+				props = append(props, fmt.Sprintf("endColumn=%d", rng.End.Column))
+			}
+		}
+
+		title := diag.Summary
+		if diag.Code != "" {
+			title = string(diag.Code) + " " + title
+		}
+
+		message := diag.Detail
+		if command == "debug" || message == "" {
+			message = strings.TrimSuffix(title+": "+diag.Detail, ": ")
+		} else {
+			props = append(props, "title="+githubEscapeProperty(title))
+		}
+		if diag.Suggestion != "" {
+			message += "\nhint: " + diag.Suggestion
+		}
+
+		buf.WriteString("::" + command)
+		if len(props) > 0 {
+			buf.WriteString(" " + strings.Join(props, ","))
+		}
+		buf.WriteString("::" + githubEscapeData(message) + "\n")
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// githubCommand returns the workflow command used to annotate the severity.
+func githubCommand(sev Severity) string {
+	switch sev {
+	case SeverityFatal, SeverityError:
+		return "error"
+	case SeverityWarn:
+		return "warning"
+	case SeverityInfo:
+		return "notice"
+	default:
+		return "debug"
+	}
+}
+
+// githubEscapeData escapes workflow command data.
+func githubEscapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// githubEscapeProperty escapes workflow command property values.
+func githubEscapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,74 @@
+package diag
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// JSONRenderer renders diagnostics as machine-readable JSON lines, one JSON
+// object per diagnostic.
+type JSONRenderer struct{}
+
+// Render implements Renderer.
+func (r *JSONRenderer) Render(w io.Writer, diags *Diagnostics) error {
+	enc := json.NewEncoder(w)
+	for _, diag := range diags.All() {
+		if err := enc.Encode(diag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jsonDiagnostic is the JSON representation of a Diagnostic.
+type jsonDiagnostic struct {
+	Severity   string     `json:"severity"`
+	Component  string     `json:"component,omitempty"`
+	Path       string     `json:"path,omitempty"`
+	Code       string     `json:"code,omitempty"`
+	Summary    string     `json:"summary"`
+	Detail     string     `json:"detail,omitempty"`
+	Suggestion string     `json:"suggestion,omitempty"`
+	Range      *jsonRange `json:"range,omitempty"`
+	Cause      string     `json:"cause,omitempty"`
+}
+
+// jsonRange is the JSON representation of a Range.
+type jsonRange struct {
+	Filename    string `json:"filename,omitempty"`
+	StartLine   int    `json:"startLine,omitempty"`
+	StartColumn int    `json:"startColumn,omitempty"`
+	EndLine     int    `json:"endLine,omitempty"`
+	EndColumn   int    `json:"endColumn,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Diagnostic) MarshalJSON() ([]byte, error) {
+	v := jsonDiagnostic{
+		Severity:   strings.ToLower(d.Severity.String()),
+		Path:       d.Path,
+		Code:       string(d.Code),
+		Summary:    d.Summary,
+		Detail:     d.Detail,
+		Suggestion: d.Suggestion,
+	}
+	if d.Component != componentInvalid {
+		v.Component = d.Component.String()
+	}
+	if d.Cause != nil {
+		v.Cause = d.Cause.Error()
+	}
+	if d.Range != nil {
+		v.Range = &jsonRange{
+			Filename:    d.Range.Filename,
+			StartLine:   d.Range.Start.Line,
+			StartColumn: d.Range.Start.Column,
+			EndLine:     d.Range.End.Line,
+			EndColumn:   d.Range.End.Column,
+		}
+	}
+
+	return json.Marshal(v)
+}
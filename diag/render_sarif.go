@@ -0,0 +1,256 @@
+package diag
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// sarifVersion is the version of SARIF rendered.
+	sarifVersion = "2.1.0"
+	// sarifSchema is the JSON schema of the SARIF version rendered.
+	sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+	// sarifSrcRoot is the URI base ID that paths within BaseDir are relative
+	// to.
+	sarifSrcRoot = "SRCROOT"
+)
+
+// SARIFRenderer renders diagnostics as a SARIF log, enabling diagnostics to be
+// uploaded to code-scanning tools.
+type SARIFRenderer struct {
+	// ToolName names the tool reported as producing the diagnostics. If empty,
+	// defaults to "configurator".
+	ToolName string
+	// ToolVersion optionally reports the version of the tool.
+	ToolVersion string
+	// InformationURI optionally links to documentation for the tool.
+	InformationURI string
+	// BaseDir optionally specifies the root of the repository, so that the
+	// config files within it are reported relative to the repository, as
+	// code-scanning tools expect. Otherwise, config files are reported as
+	// absolute file URIs.
+	BaseDir string
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	Name             string        `json:"name,omitempty"`
+	ShortDescription *sarifMessage `json:"shortDescription,omitempty"`
+	Help             *sarifMessage `json:"help,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId,omitempty"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// Render implements Renderer.
+func (r *SARIFRenderer) Render(w io.Writer, diags *Diagnostics) error {
+	name := r.ToolName
+	if name == "" {
+		name = "configurator"
+	}
+
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           name,
+			Version:        r.ToolVersion,
+			InformationURI: r.InformationURI,
+		}},
+		Results: []sarifResult{},
+	}
+	base := r.baseDir()
+	if base != "" {
+		run.OriginalURIBaseIDs = map[string]sarifArtifactLocation{
+			sarifSrcRoot: {URI: strings.TrimSuffix(fileURI(base), "/") + "/"},
+		}
+	}
+
+	seen := make(map[Code]bool)
+	for _, diag := range diags.All() {
+		run.Results = append(run.Results, sarifResultFor(diag, base))
+
+		if diag.Code != "" && !seen[diag.Code] {
+			seen[diag.Code] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRuleFor(diag.Code))
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{run},
+	})
+}
+
+// sarifRuleFor describes the code as a SARIF rule, from the code's registered
+// information.
+func sarifRuleFor(code Code) sarifRule {
+	rule := sarifRule{ID: string(code)}
+	if info, ok := LookupCode(code); ok {
+		rule.Name = info.Name
+		if info.Description != "" {
+			rule.ShortDescription = &sarifMessage{Text: info.Description}
+		}
+		if info.Suggestion != "" {
+			rule.Help = &sarifMessage{Text: info.Suggestion}
+		}
+	}
+
+	return rule
+}
+
+// sarifResultFor converts the diagnostic into a SARIF result, locating files
+// within the base directory relative to it, if provided.
+func sarifResultFor(diag Diagnostic, base string) sarifResult {
+	text := diag.Summary
+	if diag.Detail != "" {
+		text += ": " + diag.Detail
+	}
+	if diag.Suggestion != "" {
+		text += " (" + diag.Suggestion + ")"
+	}
+
+	result := sarifResult{
+		RuleID:  string(diag.Code),
+		Level:   sarifLevel(diag.Severity),
+		Message: sarifMessage{Text: text},
+	}
+
+	if rng := diag.Range; rng != nil && rng.Filename != "" {
+		location := sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocationFor(rng.Filename, base),
+		}
+		if rng.Start.Line > 0 {
+			location.Region = &sarifRegion{
+				StartLine:   rng.Start.Line,
+				StartColumn: rng.Start.Column,
+				EndLine:     rng.End.Line,
+			}
+			if rng.End.Column > 0 {
+				// SARIF regions end after the last character.
+				location.Region.EndColumn = rng.End.Column + 1
+			}
+		}
+
+		result.Locations = []sarifLocation{{PhysicalLocation: location}}
+	}
+
+	return result
+}
+
+// baseDir returns the absolute path of the base directory, if provided.
+func (r *SARIFRenderer) baseDir() string {
+	if r.BaseDir == "" {
+		return ""
+	}
+
+	base, err := filepath.Abs(r.BaseDir)
+	if err != nil {
+		return ""
+	}
+
+	return base
+}
+
+// sarifArtifactLocationFor converts the filename into a SARIF artifact
+// location, relative to the base directory if the file is within it,
+// otherwise as an absolute file URI.
+func sarifArtifactLocationFor(filename, base string) sarifArtifactLocation {
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		path = filename
+	}
+
+	if base != "" {
+		rel, err := filepath.Rel(base, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return sarifArtifactLocation{
+				URI:       (&url.URL{Path: filepath.ToSlash(rel)}).String(),
+				URIBaseID: sarifSrcRoot,
+			}
+		}
+	}
+
+	return sarifArtifactLocation{URI: fileURI(path)}
+}
+
+// fileURI converts the absolute path into a file URI, for example,
+// C:\app\config.yaml is converted into file:///C:/app/config.yaml.
+func fileURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// windows paths start with the volume name.
+		path = "/" + path
+	}
+
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// sarifLevel converts the severity into a SARIF level.
+func sarifLevel(sev Severity) string {
+	switch sev {
+	case SeverityFatal, SeverityError:
+		return "error"
+	case SeverityWarn:
+		return "warning"
+	default:
+		return "note"
+	}
+}
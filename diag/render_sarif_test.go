@@ -0,0 +1,49 @@
+package diag
+
+import "testing"
+
+func TestSARIFArtifactLocationFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		base     string
+		want     sarifArtifactLocation
+	}{
+		{
+			name:     "absolute file URI",
+			filename: "/etc/app/config.yaml",
+			want:     sarifArtifactLocation{URI: "file:///etc/app/config.yaml"},
+		},
+		{
+			name:     "escaped file URI",
+			filename: "/home/user/my app/config 1.yaml",
+			want:     sarifArtifactLocation{URI: "file:///home/user/my%20app/config%201.yaml"},
+		},
+		{
+			name:     "relative to the base directory",
+			filename: "/repo/deploy/config.yaml",
+			base:     "/repo",
+			want:     sarifArtifactLocation{URI: "deploy/config.yaml", URIBaseID: sarifSrcRoot},
+		},
+		{
+			name:     "outside of the base directory",
+			filename: "/etc/app/config.yaml",
+			base:     "/repo",
+			want:     sarifArtifactLocation{URI: "file:///etc/app/config.yaml"},
+		},
+		{
+			name:     "sibling of the base directory",
+			filename: "/repository/config.yaml",
+			base:     "/repo",
+			want:     sarifArtifactLocation{URI: "file:///repository/config.yaml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sarifArtifactLocationFor(tt.filename, tt.base); got != tt.want {
+				t.Errorf("sarifArtifactLocationFor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
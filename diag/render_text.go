@@ -0,0 +1,107 @@
+package diag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ANSI escape codes used to color rendered text.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// TextRenderer renders diagnostics as human-readable text, grouped by
+// component, for example:
+//
+//	Local Config File:
+//	  WARN  CFG1011 unknown-key [/etc/app/config.yaml] at /etc/app/config.yaml:2:1: Unknown Config Key
+//	        The key "prot" is not supported by the config. Did you mean "port"?
+//	        hint: Correct the spelling of the key, or remove it.
+type TextRenderer struct {
+	// Color enables rendering ANSI colored output.
+	Color bool
+}
+
+// Render implements Renderer.
+func (r *TextRenderer) Render(w io.Writer, diags *Diagnostics) error {
+	groups := diags.GroupByComponent()
+
+	var buf bytes.Buffer
+	for i, component := range slices.Sorted(maps.Keys(groups)) {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+
+		name := component.String()
+		if component == componentInvalid {
+			name = "General"
+		}
+		_, _ = fmt.Fprintf(&buf, "%s:\n", r.style(ansiBold, name))
+
+		for diag := range groups[component].Seq() {
+			r.renderDiagnostic(&buf, diag)
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// renderDiagnostic renders the diagnostic as an entry within its component
+// group.
+func (r *TextRenderer) renderDiagnostic(buf *bytes.Buffer, diag Diagnostic) {
+	severity := strings.ToUpper(diag.Severity.String())
+	_, _ = fmt.Fprintf(buf, "  %s", r.style(severityColor(diag.Severity), fmt.Sprintf("%-5s", severity)))
+
+	if diag.Code != "" {
+		_, _ = fmt.Fprintf(buf, " %s", r.style(ansiDim, diag.Code.String()))
+	}
+	if diag.Path != "" {
+		_, _ = fmt.Fprintf(buf, " [%s]", diag.Path)
+	}
+	if diag.Range != nil {
+		_, _ = fmt.Fprintf(buf, " at %s", diag.Range)
+	}
+	_, _ = fmt.Fprintf(buf, ": %s\n", r.style(ansiBold, diag.Summary))
+
+	if diag.Detail != "" {
+		for _, line := range strings.Split(diag.Detail, "\n") {
+			_, _ = fmt.Fprintf(buf, "        %s\n", line)
+		}
+	}
+	if diag.Suggestion != "" {
+		_, _ = fmt.Fprintf(buf, "        %s %s\n", r.style(ansiCyan, "hint:"), diag.Suggestion)
+	}
+}
+
+// style wraps the text in the ANSI escape code, if color is enabled.
+func (r *TextRenderer) style(code, text string) string {
+	if !r.Color || code == "" {
+		return text
+	}
+
+	return code + text + ansiReset
+}
+
+// severityColor returns the ANSI escape code used to color the severity.
+func severityColor(sev Severity) string {
+	switch sev {
+	case SeverityFatal, SeverityError:
+		return ansiRed
+	case SeverityWarn:
+		return ansiYellow
+	case SeverityInfo:
+		return ansiCyan
+	default:
+		return ansiDim
+	}
+}
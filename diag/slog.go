@@ -0,0 +1,114 @@
+package diag
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	// LevelTrace is the slog level diagnostics at SeverityTrace are logged
+	// at, below slog.LevelDebug.
+	LevelTrace = slog.LevelDebug - 4
+	// LevelFatal is the slog level diagnostics at SeverityFatal are logged
+	// at, above slog.LevelError.
+	LevelFatal = slog.LevelError + 4
+)
+
+// Attribute keys used when logging diagnostics.
+const (
+	SlogComponentKey  = "component"
+	SlogPathKey       = "path"
+	SlogCodeKey       = "code"
+	SlogSummaryKey    = "summary"
+	SlogDetailKey     = "detail"
+	SlogSuggestionKey = "suggestion"
+	SlogRangeKey      = "range"
+)
+
+// Level returns the slog level the severity is logged at.
+func (l Severity) Level() slog.Level {
+	switch l {
+	case SeverityFatal:
+		return LevelFatal
+	case SeverityError:
+		return slog.LevelError
+	case SeverityWarn:
+		return slog.LevelWarn
+	case SeverityInfo:
+		return slog.LevelInfo
+	case SeverityDebug:
+		return slog.LevelDebug
+	default:
+		return LevelTrace
+	}
+}
+
+// Record returns the diagnostic as a slog record, with the summary as the
+// message, and the component, path, code, summary, detail, suggestion and
+// range as attributes.
+func (d Diagnostic) Record(t time.Time) slog.Record {
+	r := slog.NewRecord(t, d.Severity.Level(), d.Summary, 0)
+
+	if d.Component != componentInvalid {
+		r.AddAttrs(slog.String(SlogComponentKey, d.Component.String()))
+	}
+	if d.Path != "" {
+		r.AddAttrs(slog.String(SlogPathKey, d.Path))
+	}
+	if d.Code != "" {
+		r.AddAttrs(slog.String(SlogCodeKey, string(d.Code)))
+	}
+	r.AddAttrs(slog.String(SlogSummaryKey, d.Summary))
+	if d.Detail != "" {
+		r.AddAttrs(slog.String(SlogDetailKey, d.Detail))
+	}
+	if d.Suggestion != "" {
+		r.AddAttrs(slog.String(SlogSuggestionKey, d.Suggestion))
+	}
+	if d.Range != nil {
+		r.AddAttrs(slog.String(SlogRangeKey, d.Range.String()))
+	}
+
+	return r
+}
+
+// Log emits each of the diagnostics to the handler, skipping diagnostics at
+// levels the handler isn't enabled for.
+func Log(ctx context.Context, h slog.Handler, diags *Diagnostics) error {
+	for _, diag := range diags.All() {
+		if err := LogDiagnostic(ctx, h, diag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LogDiagnostic emits the diagnostic to the handler, if the handler is
+// enabled for the diagnostic's level.
+func LogDiagnostic(ctx context.Context, h slog.Handler, diag Diagnostic) error {
+	if !h.Enabled(ctx, diag.Severity.Level()) {
+		return nil
+	}
+
+	return h.Handle(ctx, diag.Record(time.Now()))
+}
+
+// ReplaceLevelAttr names the custom trace and fatal levels when provided as
+// slog.HandlerOptions.ReplaceAttr, otherwise they are logged as DEBUG-4 and
+// ERROR+4.
+func ReplaceLevelAttr(_ []string, a slog.Attr) slog.Attr {
+	if a.Key != slog.LevelKey {
+		return a
+	}
+
+	switch a.Value.Any() {
+	case LevelTrace:
+		a.Value = slog.StringValue("TRACE")
+	case LevelFatal:
+		a.Value = slog.StringValue("FATAL")
+	}
+
+	return a
+}
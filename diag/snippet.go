@@ -0,0 +1,157 @@
+package diag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// SnippetRenderer renders diagnostics along with a snippet of the source they
+// refer to, underlining the offending source with carets, for example:
+//
+//	Warn[CFG1011]: Unknown Config Key
+//	  --> config.yaml:2:1
+//	   |
+//	 2 | prot: 8080
+//	   | ^^^^
+//	   |
+//	   = The key "prot" is not supported by the config. Did you mean "port"?
+//	   = help: Correct the spelling of the key, or remove it.
+//
+// Diagnostics without a source range are rendered without a snippet.
+type SnippetRenderer struct {
+	// Source returns the content of the named source file. If nil, defaults to
+	// os.ReadFile. configurator.Options.ReadFile can be provided to read
+	// sources from the same filesystem config files are discovered from.
+	Source func(filename string) ([]byte, error)
+	// Context is the number of lines of source to render before and after the
+	// offending lines.
+	Context int
+
+	// sources caches the lines of each source file read.
+	sources map[string][]string
+}
+
+// Render implements Renderer.
+func (r *SnippetRenderer) Render(w io.Writer, diags *Diagnostics) error {
+	for i, diag := range diags.All() {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+
+		if err := r.RenderDiagnostic(w, diag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RenderDiagnostic renders the diagnostic.
+func (r *SnippetRenderer) RenderDiagnostic(w io.Writer, diag Diagnostic) error {
+	var buf bytes.Buffer
+
+	_, _ = fmt.Fprint(&buf, diag.Severity)
+	if diag.Code != "" {
+		_, _ = fmt.Fprintf(&buf, "[%s]", string(diag.Code))
+	}
+	_, _ = fmt.Fprintf(&buf, ": %s\n", diag.Summary)
+
+	gutter := "  "
+	if rng := diag.Range; rng != nil {
+		lines, ok := r.lines(rng.Filename)
+		first := max(1, rng.Start.Line-r.Context)
+		last := min(len(lines), max(rng.Start.Line, rng.End.Line)+r.Context)
+
+		if ok && rng.Start.Line > 0 && rng.Start.Line <= len(lines) {
+			gutter = strings.Repeat(" ", len(fmt.Sprint(last))+1)
+			_, _ = fmt.Fprintf(&buf, "%s--> %s\n", gutter[1:], rng)
+			_, _ = fmt.Fprintf(&buf, "%s|\n", gutter)
+
+			for n := first; n <= last; n++ {
+				line := lines[n-1]
+				_, _ = fmt.Fprintf(&buf, "%*d | %s\n", len(gutter)-1, n, line)
+				if n == rng.Start.Line {
+					_, _ = fmt.Fprintf(&buf, "%s| %s\n", gutter, underline(line, *rng))
+				}
+			}
+			_, _ = fmt.Fprintf(&buf, "%s|\n", gutter)
+		} else {
+			_, _ = fmt.Fprintf(&buf, "%s--> %s\n", gutter[1:], rng)
+		}
+	} else if diag.Path != "" {
+		_, _ = fmt.Fprintf(&buf, "%s--> %s\n", gutter[1:], diag.Path)
+	}
+
+	if diag.Detail != "" {
+		_, _ = fmt.Fprintf(&buf, "%s= %s\n", gutter, diag.Detail)
+	}
+	if diag.Suggestion != "" {
+		_, _ = fmt.Fprintf(&buf, "%s= help: %s\n", gutter, diag.Suggestion)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// lines returns the lines of the named source file.
+func (r *SnippetRenderer) lines(filename string) ([]string, bool) {
+	if lines, ok := r.sources[filename]; ok {
+		return lines, lines != nil
+	}
+
+	source := r.Source
+	if source == nil {
+		source = os.ReadFile
+	}
+
+	var lines []string
+	if data, err := source(filename); err == nil {
+		lines = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimSuffix(line, "\r")
+		}
+	}
+
+	if r.sources == nil {
+		r.sources = make(map[string][]string)
+	}
+	r.sources[filename] = lines
+
+	return lines, lines != nil
+}
+
+// underline returns the carets underlining the range on the start line. The
+// indentation before the carets is copied from the source, so that tabs line
+// up.
+func underline(line string, rng Range) string {
+	runes := []rune(line)
+
+	start, end := rng.Start.Column-1, len(runes)
+	if start < 0 {
+		// the column is unknown, so underline the whole line.
+		start = utf8.RuneCountInString(line) - utf8.RuneCountInString(strings.TrimLeft(line, " \t"))
+	} else if rng.End.Line == rng.Start.Line && rng.End.Column >= rng.Start.Column {
+		end = rng.End.Column
+	} else if rng.End.Line <= rng.Start.Line {
+		end = start + 1
+	}
+	start = min(start, len(runes))
+	end = max(start+1, min(end, len(runes)))
+
+	var indent strings.Builder
+	for _, c := range runes[:start] {
+		if c == '\t' {
+			indent.WriteRune('\t')
+		} else {
+			indent.WriteRune(' ')
+		}
+	}
+
+	return indent.String() + strings.Repeat("^", end-start)
+}
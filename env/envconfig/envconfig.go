@@ -3,12 +3,15 @@ package envconfig
 import (
 	"strings"
 
-	"github.com/kelseyhightower/envconfig"
-
 	"github.com/matthewhartstonge/configurator"
+	"github.com/matthewhartstonge/configurator/diag"
 )
 
-var _ configurator.ConfigTypeable = (*EnvConfig)(nil)
+var (
+	_ configurator.ConfigTypeable    = (*EnvConfig)(nil)
+	_ configurator.UnknownKeyChecker = (*EnvConfig)(nil)
+	_ configurator.FieldSourcer      = (*EnvConfig)(nil)
+)
 
 func New(config configurator.ConfigImplementer) *EnvConfig {
 	return &EnvConfig{
@@ -20,12 +23,61 @@ func New(config configurator.ConfigImplementer) *EnvConfig {
 
 type EnvConfig struct {
 	configurator.ConfigType
+
+	// Strict configures how environment variables prefixed with the
+	// application name, that are unknown to the config implementer, are
+	// reported. Defaults to configurator.StrictIgnore.
+	Strict configurator.StrictMode
+	// prefix stores the environment variable prefix of the last parse.
+	prefix string
+	// sources stores the environment variable that set each field by the
+	// last parse.
+	sources map[string]string
+	// unknown stores the unknown environment variables found by the last
+	// parse.
+	unknown []configurator.UnknownKey
 }
 
 func (e EnvConfig) Type() string {
 	return "EnvConfig configurator"
 }
 
-func (e *EnvConfig) Parse(cfg *configurator.Config) (string, error) {
-	return strings.ToTitle(cfg.AppName), envconfig.Process(cfg.AppName, e.Config)
+// Parse processes the environment variables prefixed with the application
+// name, looked up from the environment provided by the options.
+func (e *EnvConfig) Parse(opts *configurator.Options) (string, error) {
+	e.unknown, e.sources = nil, nil
+
+	prefix := strings.ToTitle(opts.AppName)
+	e.prefix = prefix
+	env := opts.Environment
+	if env == nil {
+		env = configurator.OSEnvironment{}
+	}
+
+	sources, err := process(opts.AppName, e.Config, env.LookupEnv)
+	if err != nil {
+		return prefix, err
+	}
+	e.sources = sources
+
+	if e.Strict == configurator.StrictIgnore {
+		return prefix, nil
+	}
+
+	e.unknown, err = unknownVars(opts.AppName, e.Config, env, opts.ProfileEnv)
+
+	return prefix, err
+}
+
+// FieldSources returns the environment variable that set each field of the
+// config implementer by the last parse.
+func (e *EnvConfig) FieldSources() map[string]string {
+	return e.sources
+}
+
+// CheckUnknownKeys reports each environment variable found by the last parse
+// that is unknown to the config implementer, returning false if the config
+// has been rejected.
+func (e *EnvConfig) CheckUnknownKeys(diags *diag.Diagnostics, component diag.Component) bool {
+	return e.Strict.Report(diags, component, e.prefix, e.unknown)
 }
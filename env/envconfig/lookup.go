@@ -0,0 +1,377 @@
+// The environment variable gathering and decoding within this file, varInfo,
+// gatherRegexp, acronymRegexp, process, gatherInfo, isDecodable, processField,
+// interfaceFrom and isTrue, is adapted from github.com/kelseyhightower/envconfig
+// v1.4.0, which doesn't export it, under the following license:
+//
+// Copyright (c) 2013 Kelsey Hightower
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package envconfig
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/matthewhartstonge/configurator"
+)
+
+// LookupFunc retrieves the value of the environment variable named by the key,
+// reporting whether the variable is present.
+type LookupFunc func(key string) (string, bool)
+
+// varInfo describes an environment variable gathered from the specification.
+type varInfo struct {
+	Name string
+	// Path is the dotted Go field path of the field within the specification.
+	Path  string
+	Alt   string
+	Key   string
+	Field reflect.Value
+	Tags  reflect.StructTag
+}
+
+// process populates the specified struct from the provided lookup, matching
+// the semantics of envconfig.Process, which only ever reads the process
+// environment. The process environment is looked up via
+// configurator.OSEnvironment, so that every environment is decoded the same.
+//
+// The environment variable that set each field is returned, keyed by the
+// field's dotted Go field path.
+func process(prefix string, spec interface{}, lookup LookupFunc) (map[string]string, error) {
+	infos, err := gatherInfo(prefix, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]string)
+	for _, info := range infos {
+		key := info.Key
+		value, ok := lookup(key)
+		if !ok && info.Alt != "" {
+			key = info.Alt
+			value, ok = lookup(key)
+		}
+
+		def := info.Tags.Get("default")
+		if def != "" && !ok {
+			value = def
+		}
+
+		if !ok && def == "" {
+			if isTrue(info.Tags.Get("required")) {
+				key := info.Key
+				if info.Alt != "" {
+					key = info.Alt
+				}
+				return nil, fmt.Errorf("required key %s missing value", key)
+			}
+			continue
+		}
+
+		if err := processField(value, info.Field); err != nil {
+			return nil, &envconfig.ParseError{
+				KeyName:   info.Key,
+				FieldName: info.Name,
+				TypeName:  info.Field.Type().String(),
+				Value:     value,
+				Err:       err,
+			}
+		}
+		if ok {
+			sources[info.Path] = key
+		}
+	}
+
+	return sources, nil
+}
+
+var (
+	gatherRegexp  = regexp.MustCompile("([^A-Z]+|[A-Z]+[^A-Z]+|[A-Z]+)")
+	acronymRegexp = regexp.MustCompile("([A-Z]+)([A-Z][^A-Z]+)")
+)
+
+// gatherInfo gathers the environment variables of the specified struct.
+//
+// gatherInfo is adapted from envconfig v1.4.0 so that variable naming stays
+// consistent with envconfig.Process. Any changes to envconfig's variable
+// naming must be mirrored here when upgrading.
+func gatherInfo(prefix string, spec interface{}) ([]varInfo, error) {
+	s := reflect.ValueOf(spec)
+	if s.Kind() != reflect.Pointer {
+		return nil, envconfig.ErrInvalidSpecification
+	}
+	s = s.Elem()
+	if s.Kind() != reflect.Struct {
+		return nil, envconfig.ErrInvalidSpecification
+	}
+	typeOfSpec := s.Type()
+
+	infos := make([]varInfo, 0, s.NumField())
+	for i := 0; i < s.NumField(); i++ {
+		f := s.Field(i)
+		ftype := typeOfSpec.Field(i)
+		if !f.CanSet() || isTrue(ftype.Tag.Get("ignored")) {
+			continue
+		}
+
+		for f.Kind() == reflect.Pointer {
+			if f.IsNil() {
+				if f.Type().Elem().Kind() != reflect.Struct {
+					// nil pointer to a non-struct: leave it alone.
+					break
+				}
+				// nil pointer to a struct: create a zero instance.
+				f.Set(reflect.New(f.Type().Elem()))
+			}
+			f = f.Elem()
+		}
+
+		info := varInfo{
+			Name:  ftype.Name,
+			Path:  ftype.Name,
+			Field: f,
+			Tags:  ftype.Tag,
+			Alt:   strings.ToUpper(ftype.Tag.Get("envconfig")),
+		}
+
+		// default to the field name as the variable name, un-picking camel
+		// casing as separate words if asked to.
+		info.Key = info.Name
+		if isTrue(ftype.Tag.Get("split_words")) {
+			words := gatherRegexp.FindAllStringSubmatch(ftype.Name, -1)
+			if len(words) > 0 {
+				var name []string
+				for _, words := range words {
+					if m := acronymRegexp.FindStringSubmatch(words[0]); len(m) == 3 {
+						name = append(name, m[1], m[2])
+					} else {
+						name = append(name, words[0])
+					}
+				}
+
+				info.Key = strings.Join(name, "_")
+			}
+		}
+		if info.Alt != "" {
+			info.Key = info.Alt
+		}
+		if prefix != "" {
+			info.Key = fmt.Sprintf("%s_%s", prefix, info.Key)
+		}
+		info.Key = strings.ToUpper(info.Key)
+		infos = append(infos, info)
+
+		if f.Kind() == reflect.Struct && !isDecodable(f) {
+			// gather the nested struct's variables in place of the struct.
+			innerPrefix := prefix
+			if !ftype.Anonymous {
+				innerPrefix = info.Key
+			}
+
+			embeddedInfos, err := gatherInfo(innerPrefix, f.Addr().Interface())
+			if err != nil {
+				return nil, err
+			}
+			for i := range embeddedInfos {
+				embeddedInfos[i].Path = ftype.Name + "." + embeddedInfos[i].Path
+			}
+			infos = append(infos[:len(infos)-1], embeddedInfos...)
+		}
+	}
+
+	return infos, nil
+}
+
+// isDecodable reports whether the field decodes itself from a value.
+func isDecodable(field reflect.Value) bool {
+	_, decoder := interfaceFrom[envconfig.Decoder](field)
+	_, setter := interfaceFrom[envconfig.Setter](field)
+	_, text := interfaceFrom[encoding.TextUnmarshaler](field)
+	_, binary := interfaceFrom[encoding.BinaryUnmarshaler](field)
+
+	return decoder || setter || text || binary
+}
+
+// unknownVars returns each environment variable prefixed with the application
+// name that isn't known to the specified struct, ignoring the provided
+// environment variables that are known elsewhere.
+func unknownVars(prefix string, spec interface{}, env configurator.Environment, ignore ...string) ([]configurator.UnknownKey, error) {
+	infos, err := gatherInfo(prefix, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make([]string, 0, len(infos))
+	for _, info := range infos {
+		known = append(known, info.Key)
+	}
+
+	varPrefix := strings.ToUpper(prefix) + "_"
+	var unknown []configurator.UnknownKey
+	for _, kv := range env.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(key, varPrefix) || slices.Contains(known, key) || slices.Contains(ignore, key) {
+			continue
+		}
+
+		unknown = append(unknown, configurator.UnknownKey{
+			Path:        key,
+			Suggestions: configurator.Suggest(key, known),
+		})
+	}
+	slices.SortFunc(unknown, func(a, b configurator.UnknownKey) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return unknown, nil
+}
+
+// processField decodes the value into the field.
+func processField(value string, field reflect.Value) error {
+	typ := field.Type()
+
+	if decoder, ok := interfaceFrom[envconfig.Decoder](field); ok {
+		return decoder.Decode(value)
+	}
+	if setter, ok := interfaceFrom[envconfig.Setter](field); ok {
+		return setter.Set(value)
+	}
+	if t, ok := interfaceFrom[encoding.TextUnmarshaler](field); ok {
+		return t.UnmarshalText([]byte(value))
+	}
+	if b, ok := interfaceFrom[encoding.BinaryUnmarshaler](field); ok {
+		return b.UnmarshalBinary([]byte(value))
+	}
+
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+		if field.IsNil() {
+			field.Set(reflect.New(typ))
+		}
+		field = field.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var (
+			val int64
+			err error
+		)
+		if typ == reflect.TypeOf(time.Duration(0)) {
+			var d time.Duration
+			d, err = time.ParseDuration(value)
+			val = int64(d)
+		} else {
+			val, err = strconv.ParseInt(value, 0, typ.Bits())
+		}
+		if err != nil {
+			return err
+		}
+		field.SetInt(val)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		val, err := strconv.ParseUint(value, 0, typ.Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(val)
+	case reflect.Bool:
+		val, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(val)
+	case reflect.Float32, reflect.Float64:
+		val, err := strconv.ParseFloat(value, typ.Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(val)
+	case reflect.Slice:
+		sl := reflect.MakeSlice(typ, 0, 0)
+		if typ.Elem().Kind() == reflect.Uint8 {
+			sl = reflect.ValueOf([]byte(value))
+		} else if strings.TrimSpace(value) != "" {
+			vals := strings.Split(value, ",")
+			sl = reflect.MakeSlice(typ, len(vals), len(vals))
+			for i, val := range vals {
+				if err := processField(val, sl.Index(i)); err != nil {
+					return err
+				}
+			}
+		}
+		field.Set(sl)
+	case reflect.Map:
+		mp := reflect.MakeMap(typ)
+		if strings.TrimSpace(value) != "" {
+			for _, pair := range strings.Split(value, ",") {
+				kv := strings.Split(pair, ":")
+				if len(kv) != 2 {
+					return fmt.Errorf("invalid map item: %q", pair)
+				}
+				k := reflect.New(typ.Key()).Elem()
+				if err := processField(kv[0], k); err != nil {
+					return err
+				}
+				v := reflect.New(typ.Elem()).Elem()
+				if err := processField(kv[1], v); err != nil {
+					return err
+				}
+				mp.SetMapIndex(k, v)
+			}
+		}
+		field.Set(mp)
+	}
+
+	return nil
+}
+
+// interfaceFrom returns the field, or its address, as the given interface.
+func interfaceFrom[I any](field reflect.Value) (I, bool) {
+	var zero I
+	if !field.CanInterface() {
+		return zero, false
+	}
+	if i, ok := field.Interface().(I); ok {
+		return i, true
+	}
+	if field.CanAddr() {
+		if i, ok := field.Addr().Interface().(I); ok {
+			return i, true
+		}
+	}
+
+	return zero, false
+}
+
+// isTrue reports whether the string parses as true.
+func isTrue(s string) bool {
+	b, _ := strconv.ParseBool(s)
+	return b
+}
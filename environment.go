@@ -0,0 +1,116 @@
+package configurator
+
+import (
+	"errors"
+	"os"
+	"sort"
+)
+
+var (
+	_ Environment = OSEnvironment{}
+	_ Environment = MapEnvironment{}
+)
+
+// Environment provides access to the environment that configuration is
+// discovered and parsed from. Supplying an Environment enables tests and
+// embedded use to provide a synthetic environment, without needing to mutate
+// the process environment.
+type Environment interface {
+	// LookupEnv retrieves the value of the environment variable named by the
+	// key, reporting whether the variable is present.
+	LookupEnv(key string) (string, bool)
+	// Environ returns the environment variables in the form "key=value".
+	Environ() []string
+	// UserHomeDir returns the user's home directory.
+	UserHomeDir() (string, error)
+	// UserConfigDir returns the default root directory to use for user
+	// specific configuration data.
+	UserConfigDir() (string, error)
+	// Getwd returns the current working directory.
+	Getwd() (string, error)
+}
+
+// OSEnvironment provides the environment of the running process.
+type OSEnvironment struct{}
+
+// LookupEnv implements Environment.
+func (OSEnvironment) LookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+// Environ implements Environment.
+func (OSEnvironment) Environ() []string {
+	return os.Environ()
+}
+
+// UserHomeDir implements Environment.
+func (OSEnvironment) UserHomeDir() (string, error) {
+	return os.UserHomeDir()
+}
+
+// UserConfigDir implements Environment.
+func (OSEnvironment) UserConfigDir() (string, error) {
+	return os.UserConfigDir()
+}
+
+// Getwd implements Environment.
+func (OSEnvironment) Getwd() (string, error) {
+	return os.Getwd()
+}
+
+// MapEnvironment provides a synthetic environment backed by a map of
+// environment variables and statically defined directories.
+type MapEnvironment struct {
+	// Vars holds the environment variables.
+	Vars map[string]string
+	// HomeDir is returned as the user's home directory.
+	HomeDir string
+	// ConfigDir is returned as the user's configuration directory.
+	ConfigDir string
+	// WorkingDir is returned as the current working directory.
+	WorkingDir string
+}
+
+// LookupEnv implements Environment.
+func (m MapEnvironment) LookupEnv(key string) (string, bool) {
+	v, ok := m.Vars[key]
+	return v, ok
+}
+
+// Environ implements Environment.
+func (m MapEnvironment) Environ() []string {
+	env := make([]string, 0, len(m.Vars))
+	for k, v := range m.Vars {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+
+	return env
+}
+
+// UserHomeDir implements Environment.
+func (m MapEnvironment) UserHomeDir() (string, error) {
+	if m.HomeDir == "" {
+		return "", errors.New("home directory is not defined")
+	}
+
+	return m.HomeDir, nil
+}
+
+// UserConfigDir implements Environment.
+func (m MapEnvironment) UserConfigDir() (string, error) {
+	if m.ConfigDir == "" {
+		return "", errors.New("user config directory is not defined")
+	}
+
+	return m.ConfigDir, nil
+}
+
+// Getwd implements Environment.
+func (m MapEnvironment) Getwd() (string, error) {
+	if m.WorkingDir == "" {
+		return "", errors.New("working directory is not defined")
+	}
+
+	return m.WorkingDir, nil
+}
@@ -1 +1,61 @@
 package configurator
+
+import (
+	"errors"
+
+	"github.com/matthewhartstonge/configurator/diag"
+)
+
+var (
+	// ErrConfigFileNotFound is the cause of diagnostics reporting that a
+	// config file explicitly specified, for example via the `-config-file`
+	// CLI flag, could not be found.
+	ErrConfigFileNotFound = errors.New("config file not found")
+	// ErrParseFailed is the cause of diagnostics reporting that a config
+	// source failed to parse. The underlying parse error, for example a
+	// *SourceError, is also wrapped.
+	ErrParseFailed = errors.New("config parse failed")
+	// ErrValidationFailed is the cause of fatal and error diagnostics reported
+	// by a config implementer's Validate method.
+	ErrValidationFailed = errors.New("config validation failed")
+)
+
+// SourceError is returned by file type providers to locate an error within
+// the source of a config file. Multiple source errors can be returned joined
+// with errors.Join, each of which is reported as its own diagnostic.
+type SourceError struct {
+	// Range locates the error within the config file. If the filename isn't
+	// known by the provider, it is set to the path of the parsed config file.
+	Range diag.Range
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements error.
+func (e *SourceError) Error() string {
+	return e.Range.String() + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// sourceErrors returns each source error found within the error tree.
+func sourceErrors(err error) []*SourceError {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []*SourceError
+		for _, err := range joined.Unwrap() {
+			errs = append(errs, sourceErrors(err)...)
+		}
+
+		return errs
+	}
+
+	var srcErr *SourceError
+	if errors.As(err, &srcErr) {
+		return []*SourceError{srcErr}
+	}
+
+	return nil
+}
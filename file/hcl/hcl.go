@@ -1,17 +1,26 @@
 package hcl
 
 import (
-	"os"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
 
 	hcl "github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 
 	"github.com/matthewhartstonge/configurator"
+	"github.com/matthewhartstonge/configurator/diag"
 )
 
 var _ configurator.ConfigTypeable = (*HCL)(nil)
 
+// New returns a HCL configurator for the provided config. HCL has always
+// rejected unknown arguments and blocks, so Strict defaults to
+// configurator.StrictError.
 func New(config configurator.ConfigImplementer) *HCL {
 	h := &HCL{}
 	h.ConfigFileType = configurator.NewConfigFileType(
@@ -19,6 +28,9 @@ func New(config configurator.ConfigImplementer) *HCL {
 		[]string{"hcl"},
 		unmarshal(h),
 	)
+	h.Strict = configurator.StrictError
+	h.FindUnknownKeys = unknownKeys(h)
+	h.FindKeyRange = keyRange(h)
 
 	return h
 }
@@ -34,21 +46,233 @@ func (h HCL) Type() string {
 // unmarshal is a helper function that returns a Unmarshaler for HCL files.
 func unmarshal(h *HCL) configurator.Unmarshaler {
 	return func(data []byte, v interface{}) error {
-		src, err := os.ReadFile(h.Path)
-		if err != nil {
-			return err
+		file, diags := hclsyntax.ParseConfig(data, h.Path, hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			return sourceErrors(diags)
 		}
 
-		file, diags := hclsyntax.ParseConfig(src, h.Path, hcl.Pos{Line: 1, Column: 1})
+		// unknown arguments and blocks are reported based on the strict mode,
+		// decoding continues regardless.
+		diags = slices.DeleteFunc(gohcl.DecodeBody(file.Body, nil, v), isUnknownKey)
 		if diags.HasErrors() {
-			return diags
+			return sourceErrors(diags)
+		}
+
+		return nil
+	}
+}
+
+// sourceErrors locates each of the HCL error diagnostics within the source.
+func sourceErrors(diags hcl.Diagnostics) error {
+	var errs []error
+	for _, hclDiag := range diags {
+		if hclDiag.Severity != hcl.DiagError {
+			continue
+		}
+
+		err := errors.New(hclDiag.Summary)
+		if hclDiag.Detail != "" {
+			err = fmt.Errorf("%s; %s", hclDiag.Summary, hclDiag.Detail)
 		}
+		if hclDiag.Subject == nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		errs = append(errs, &configurator.SourceError{
+			Range: sourceRange(*hclDiag.Subject),
+			Err:   err,
+		})
+	}
+
+	return errors.Join(errs...)
+}
+
+// sourceRange converts the HCL range into a diagnostic range. HCL ranges end
+// after the last character, whereas diagnostic ranges end on it.
+func sourceRange(rng hcl.Range) diag.Range {
+	return diag.Range{
+		Filename: rng.Filename,
+		Start:    diag.Pos{Line: rng.Start.Line, Column: rng.Start.Column},
+		End:      diag.Pos{Line: rng.End.Line, Column: max(1, rng.End.Column-1)},
+	}
+}
 
-		diags = gohcl.DecodeBody(file.Body, nil, v)
+// isUnknownKey reports whether the diagnostic reports an unknown argument or
+// block.
+func isUnknownKey(diag *hcl.Diagnostic) bool {
+	return diag.Summary == "Unsupported argument" || diag.Summary == "Unsupported block type"
+}
+
+// unknownKeys is a helper function that returns an UnknownKeyFinder for HCL
+// files.
+func unknownKeys(h *HCL) configurator.UnknownKeyFinder {
+	return func(data []byte, v any) ([]configurator.UnknownKey, error) {
+		file, diags := hclsyntax.ParseConfig(data, h.Path, hcl.Pos{Line: 1, Column: 1})
 		if diags.HasErrors() {
-			return diags
+			return nil, diags
 		}
 
-		return nil
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			return nil, nil
+		}
+
+		var unknown []configurator.UnknownKey
+		unknownBodyKeys(&unknown, "", body, reflect.TypeOf(v))
+
+		return unknown, nil
+	}
+}
+
+// unknownBodyKeys walks the body's arguments and blocks, collecting those
+// without a matching `hcl` tagged struct field.
+func unknownBodyKeys(unknown *[]configurator.UnknownKey, prefix string, body *hclsyntax.Body, t reflect.Type) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	attrs := make(map[string]bool)
+	blocks := make(map[string]reflect.Type)
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("hcl")
+		if !ok {
+			continue
+		}
+
+		name, kind, _ := strings.Cut(tag, ",")
+		if kind != "label" && kind != "remain" {
+			names = append(names, name)
+		}
+
+		switch kind {
+		case "remain":
+			// the remaining body accepts any key.
+			return
+		case "block":
+			blocks[name] = field.Type
+		case "label":
+		default:
+			attrs[name] = true
+		}
 	}
+
+	for _, name := range slices.Sorted(maps.Keys(body.Attributes)) {
+		if !attrs[name] {
+			rng := sourceRange(body.Attributes[name].NameRange)
+			*unknown = append(*unknown, configurator.UnknownKey{
+				Path:        joinKey(prefix, name),
+				Suggestions: configurator.Suggest(name, names),
+				Range:       &rng,
+			})
+		}
+	}
+
+	for _, block := range body.Blocks {
+		path := joinKey(prefix, strings.Join(append([]string{block.Type}, block.Labels...), "."))
+
+		blockType, ok := blocks[block.Type]
+		if !ok {
+			rng := sourceRange(block.TypeRange)
+			*unknown = append(*unknown, configurator.UnknownKey{
+				Path:        path,
+				Suggestions: configurator.Suggest(block.Type, names),
+				Range:       &rng,
+			})
+			continue
+		}
+
+		for blockType.Kind() == reflect.Pointer || blockType.Kind() == reflect.Slice {
+			blockType = blockType.Elem()
+		}
+		unknownBodyKeys(unknown, path, block.Body, blockType)
+	}
+}
+
+// keyRange is a helper function that returns a KeyRangeFinder for HCL files.
+func keyRange(h *HCL) configurator.KeyRangeFinder {
+	return func(data []byte, v any, field string) (diag.Range, bool) {
+		file, diags := hclsyntax.ParseConfig(data, h.Path, hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			return diag.Range{}, false
+		}
+
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			return diag.Range{}, false
+		}
+
+		return bodyKeyRange(body, nil, reflect.TypeOf(v), strings.Split(field, "."))
+	}
+}
+
+// bodyKeyRange walks the `hcl` tagged struct fields at the Go field path down
+// through the body's blocks, returning the source range of the argument, block
+// type or label the field decodes from. Where a block is given more than once,
+// the first is located.
+func bodyKeyRange(body *hclsyntax.Body, block *hclsyntax.Block, t reflect.Type, path []string) (diag.Range, bool) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return diag.Range{}, false
+	}
+
+	labels := 0
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("hcl")
+		if !ok {
+			continue
+		}
+
+		name, kind, _ := strings.Cut(tag, ",")
+		if !strings.EqualFold(field.Name, path[0]) {
+			if kind == "label" {
+				labels++
+			}
+			continue
+		}
+
+		switch kind {
+		case "label":
+			if len(path) > 1 || block == nil || labels >= len(block.LabelRanges) {
+				return diag.Range{}, false
+			}
+			return sourceRange(block.LabelRanges[labels]), true
+		case "block":
+			for _, child := range body.Blocks {
+				if child.Type != name {
+					continue
+				}
+				if len(path) == 1 {
+					return sourceRange(child.TypeRange), true
+				}
+				return bodyKeyRange(child.Body, child, field.Type, path[1:])
+			}
+		case "remain":
+		default:
+			if attr, ok := body.Attributes[name]; ok && len(path) == 1 {
+				return sourceRange(attr.NameRange), true
+			}
+		}
+
+		return diag.Range{}, false
+	}
+
+	return diag.Range{}, false
+}
+
+// joinKey joins the key onto the dotted key path.
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
 }
@@ -1,21 +1,30 @@
 package json
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
 
 	"github.com/matthewhartstonge/configurator"
+	"github.com/matthewhartstonge/configurator/diag"
 )
 
 var _ configurator.ConfigTypeable = (*JSON)(nil)
 
 func New(config configurator.ConfigImplementer) *JSON {
-	return &JSON{
+	j := &JSON{
 		ConfigFileType: configurator.NewConfigFileType(
 			config,
 			[]string{"json"},
-			json.Unmarshal,
+			unmarshal,
 		),
 	}
+	j.FindUnknownKeys = unknownKeys
+	j.FindKeyRange = keyRange
+
+	return j
 }
 
 type JSON struct {
@@ -25,3 +34,141 @@ type JSON struct {
 func (j JSON) Type() string {
 	return "JSON configurator"
 }
+
+// unmarshal unmarshals the JSON data, locating syntax and type errors within
+// the source.
+func unmarshal(data []byte, v interface{}) error {
+	err := json.Unmarshal(data, v)
+
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return err
+	}
+
+	// offsets are reported after the offending byte has been read.
+	pos := diag.OffsetPos(data, offset-1)
+	return &configurator.SourceError{
+		Range: diag.Range{Start: pos, End: pos},
+		Err:   err,
+	}
+}
+
+// unknownKeys finds the keys in the JSON data unknown to the config.
+func unknownKeys(data []byte, v any) ([]configurator.UnknownKey, error) {
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	unknown := configurator.UnknownKeys(values, v, configurator.JSONKeys)
+	for i, key := range unknown {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		if rng, ok := findKey(dec, data, strings.Split(key.Path, ".")); ok {
+			unknown[i].Range = &rng
+		}
+	}
+
+	return unknown, nil
+}
+
+// keyRange locates the key in the JSON data that decodes into the field.
+func keyRange(data []byte, v any, field string) (diag.Range, bool) {
+	path, ok := configurator.JSONKeys.KeyPath(v, field)
+	if !ok {
+		return diag.Range{}, false
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	return findKey(dec, data, strings.Split(path, "."))
+}
+
+// findKey walks the object being decoded for the key path, returning the
+// source range of the quoted key. Keys are matched case-insensitively, as
+// encoding/json does, and may index into arrays, for example `servers[0]`.
+func findKey(dec *json.Decoder, data []byte, keys []string) (diag.Range, bool) {
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return diag.Range{}, false
+	}
+
+	name, indexes, _ := strings.Cut(keys[0], "[")
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return diag.Range{}, false
+		}
+
+		// the decoder's offset is just past the closing quote of the key.
+		end := dec.InputOffset()
+		if key, _ := tok.(string); strings.EqualFold(key, name) {
+			if len(keys) > 1 {
+				if !stepIntoIndexes(dec, indexes) {
+					return diag.Range{}, false
+				}
+				return findKey(dec, data, keys[1:])
+			}
+
+			return diag.Range{
+				Start: diag.OffsetPos(data, keyStart(data, end)),
+				End:   diag.OffsetPos(data, end-1),
+			}, true
+		}
+
+		// skip the value of other keys.
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return diag.Range{}, false
+		}
+	}
+
+	return diag.Range{}, false
+}
+
+// stepIntoIndexes steps the decoder into the array elements at the indexes,
+// for example `[0][1]`, leaving the decoder before the indexed element.
+func stepIntoIndexes(dec *json.Decoder, indexes string) bool {
+	for _, index := range strings.Split(indexes, "[") {
+		if index == "" {
+			continue
+		}
+
+		i, err := strconv.Atoi(strings.TrimSuffix(index, "]"))
+		if err != nil {
+			return false
+		}
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+			return false
+		}
+
+		// skip the elements before the index.
+		for ; i > 0 && dec.More(); i-- {
+			var value json.RawMessage
+			if err := dec.Decode(&value); err != nil {
+				return false
+			}
+		}
+		if i > 0 || !dec.More() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// keyStart returns the offset of the opening quote of the key ending at the
+// offset.
+func keyStart(data []byte, end int64) int64 {
+	for i := end - 2; i > 0; i-- {
+		if data[i] == '"' && data[i-1] != '\\' {
+			return i
+		}
+	}
+
+	return 0
+}
@@ -1,21 +1,34 @@
 package toml
 
 import (
+	"bytes"
+	"errors"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
 	toml "github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
 
 	"github.com/matthewhartstonge/configurator"
+	"github.com/matthewhartstonge/configurator/diag"
 )
 
 var _ configurator.ConfigTypeable = (*TOML)(nil)
 
 func New(config configurator.ConfigImplementer) *TOML {
-	return &TOML{
+	t := &TOML{
 		ConfigFileType: configurator.NewConfigFileType(
 			config,
 			[]string{"toml"},
-			toml.Unmarshal,
+			unmarshal,
 		),
 	}
+	t.FindUnknownKeys = unknownKeys
+	t.FindKeyRange = keyRange
+
+	return t
 }
 
 type TOML struct {
@@ -25,3 +38,125 @@ type TOML struct {
 func (t TOML) Type() string {
 	return "TOML configurator"
 }
+
+// unmarshal unmarshals the TOML data, locating decode errors within the
+// source.
+func unmarshal(data []byte, v interface{}) error {
+	err := toml.Unmarshal(data, v)
+
+	var decodeErr *toml.DecodeError
+	if !errors.As(err, &decodeErr) {
+		return err
+	}
+
+	return &configurator.SourceError{
+		Range: decodeRange(decodeErr),
+		Err:   err,
+	}
+}
+
+// unknownKeys finds the keys in the TOML data unknown to the config.
+func unknownKeys(data []byte, v any) ([]configurator.UnknownKey, error) {
+	var values map[string]any
+	if err := toml.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	unknown := configurator.UnknownKeys(values, v, configurator.TOMLKeys)
+	if len(unknown) == 0 {
+		return nil, nil
+	}
+
+	// go-toml is able to locate unknown keys when decoding strictly, which is
+	// performed into a throwaway value of the config's type.
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer {
+		return unknown, nil
+	}
+
+	err := toml.NewDecoder(bytes.NewReader(data)).
+		DisallowUnknownFields().
+		Decode(reflect.New(rv.Type().Elem()).Interface())
+
+	var strictErr *toml.StrictMissingError
+	if !errors.As(err, &strictErr) {
+		return unknown, nil
+	}
+
+	ranges := make(map[string][]diag.Range)
+	for i := range strictErr.Errors {
+		keys := strictErr.Errors[i].Key()
+		key := strings.Join(keys, ".")
+
+		// underline the whole key, rather than the start of it.
+		rng := decodeRange(&strictErr.Errors[i])
+		if len(keys) > 0 {
+			rng.End.Column += len(keys[len(keys)-1]) - 1
+		}
+		ranges[key] = append(ranges[key], rng)
+	}
+
+	for i, key := range unknown {
+		path := arrayIndex.ReplaceAllString(key.Path, "")
+		if rngs := ranges[path]; len(rngs) > 0 {
+			unknown[i].Range = &rngs[0]
+			ranges[path] = rngs[1:]
+		}
+	}
+
+	return unknown, nil
+}
+
+// keyRange locates the key in the TOML data that decodes into the field,
+// either a key/value pair, or a table header.
+func keyRange(data []byte, v any, field string) (diag.Range, bool) {
+	path, ok := configurator.TOMLKeys.KeyPath(v, field)
+	if !ok {
+		return diag.Range{}, false
+	}
+	want := strings.Split(path, ".")
+
+	var p unstable.Parser
+	p.Reset(data)
+
+	var table []string
+	for p.NextExpression() {
+		expr := p.Expression()
+
+		var keys []string
+		var last *unstable.Node
+		for key := expr.Key(); key.Next(); {
+			last = key.Node()
+			keys = append(keys, string(last.Data))
+		}
+
+		switch expr.Kind {
+		case unstable.Table, unstable.ArrayTable:
+			table = keys
+		case unstable.KeyValue:
+			keys = append(slices.Clone(table), keys...)
+		default:
+			continue
+		}
+
+		if last != nil && slices.EqualFunc(keys, want, strings.EqualFold) {
+			return diag.Range{
+				Start: diag.OffsetPos(data, int64(last.Raw.Offset)),
+				End:   diag.OffsetPos(data, int64(last.Raw.Offset+last.Raw.Length)-1),
+			}, true
+		}
+	}
+
+	return diag.Range{}, false
+}
+
+// arrayIndex matches the array indexes of a dotted key path.
+var arrayIndex = regexp.MustCompile(`\[\d+\]`)
+
+// decodeRange returns the source range of the decode error.
+func decodeRange(err *toml.DecodeError) diag.Range {
+	line, column := err.Position()
+	pos := diag.Pos{Line: line, Column: column}
+
+	return diag.Range{Start: pos, End: pos}
+}
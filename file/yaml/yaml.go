@@ -1,21 +1,31 @@
 package yaml
 
 import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
 	yaml "gopkg.in/yaml.v3"
 
 	"github.com/matthewhartstonge/configurator"
+	"github.com/matthewhartstonge/configurator/diag"
 )
 
 var _ configurator.ConfigTypeable = (*YAML)(nil)
 
 func New(config configurator.ConfigImplementer) *YAML {
-	return &YAML{
+	y := &YAML{
 		ConfigFileType: configurator.NewConfigFileType(
 			config,
 			[]string{"yaml", "yml"},
-			yaml.Unmarshal,
+			unmarshal,
 		),
 	}
+	y.FindUnknownKeys = unknownKeys
+	y.FindKeyRange = keyRange
+
+	return y
 }
 
 type YAML struct {
@@ -25,3 +35,216 @@ type YAML struct {
 func (y YAML) Type() string {
 	return "YAML configurator"
 }
+
+// errLine matches the line number yaml prefixes its error messages with.
+var errLine = regexp.MustCompile(`(?s)^(?:yaml: )?line (\d+): (.*)$`)
+
+// errValue matches the value yaml quotes in its type error messages, which is
+// shortened to its first 7 characters followed by "..." if longer than 10.
+var errValue = regexp.MustCompile("`(.*)`")
+
+// unmarshal unmarshals the YAML data, locating syntax and type errors within
+// the source.
+func unmarshal(data []byte, v interface{}) error {
+	err := yaml.Unmarshal(data, v)
+	if err == nil {
+		return nil
+	}
+
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return sourceError(nil, err.Error())
+	}
+
+	// type errors are reported against the offending node, which can be
+	// located within the source by column as well as line.
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		root = yaml.Node{}
+	}
+
+	errs := make([]error, 0, len(typeErr.Errors))
+	for _, msg := range typeErr.Errors {
+		errs = append(errs, sourceError(&root, msg))
+	}
+
+	return errors.Join(errs...)
+}
+
+// sourceError locates the yaml error message within the source, if the
+// message reports a line, using the range of the offending node found in the
+// provided document, if any.
+func sourceError(root *yaml.Node, msg string) error {
+	matches := errLine.FindStringSubmatch(msg)
+	if matches == nil {
+		return errors.New(msg)
+	}
+
+	line, _ := strconv.Atoi(matches[1])
+	pos := diag.Pos{Line: line}
+	rng := diag.Range{Start: pos, End: pos}
+
+	var value string
+	if m := errValue.FindStringSubmatch(matches[2]); m != nil {
+		value = m[1]
+	}
+	if node := valueNode(root, line, value); node != nil {
+		rng = nodeRange(node)
+	}
+
+	return &configurator.SourceError{
+		Range: rng,
+		Err:   errors.New(matches[2]),
+	}
+}
+
+// valueNode finds the value node on the line that yaml reported as failing to
+// decode. Scalars are matched by their quoted value, while mappings and
+// sequences, which yaml doesn't quote, are matched if no value was quoted.
+func valueNode(node *yaml.Node, line int, value string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			if found := valueNode(child, line, value); found != nil {
+				return found
+			}
+		}
+
+	case yaml.MappingNode, yaml.SequenceNode:
+		if node.Line == line && value == "" {
+			return node
+		}
+
+		// only the values of mappings are decoded into fields.
+		start, step := 0, 1
+		if node.Kind == yaml.MappingNode {
+			start, step = 1, 2
+		}
+		for i := start; i < len(node.Content); i += step {
+			if found := valueNode(node.Content[i], line, value); found != nil {
+				return found
+			}
+		}
+
+	case yaml.ScalarNode:
+		if node.Line == line && matchesValue(node.Value, value) {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// matchesValue reports whether the scalar value matches the value quoted by
+// yaml, which may have been shortened.
+func matchesValue(scalar, quoted string) bool {
+	if scalar == quoted {
+		return true
+	}
+
+	prefix, shortened := strings.CutSuffix(quoted, "...")
+	return shortened && len(scalar) > 10 && strings.HasPrefix(scalar, prefix)
+}
+
+// unknownKeys finds the keys in the YAML data unknown to the config.
+func unknownKeys(data []byte, v any) ([]configurator.UnknownKey, error) {
+	var values map[string]any
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	unknown := configurator.UnknownKeys(values, v, configurator.YAMLKeys)
+	if len(unknown) == 0 {
+		return nil, nil
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return unknown, nil
+	}
+
+	for i, key := range unknown {
+		if node := keyNode(&root, key.Path); node != nil {
+			rng := nodeRange(node)
+			unknown[i].Range = &rng
+		}
+	}
+
+	return unknown, nil
+}
+
+// keyRange locates the key in the YAML data that decodes into the field.
+func keyRange(data []byte, v any, field string) (diag.Range, bool) {
+	path, ok := configurator.YAMLKeys.KeyPath(v, field)
+	if !ok {
+		return diag.Range{}, false
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return diag.Range{}, false
+	}
+
+	node := keyNode(&root, path)
+	if node == nil {
+		return diag.Range{}, false
+	}
+
+	return nodeRange(node), true
+}
+
+// nodeRange returns the source range of the node, spanning the value of
+// single line scalars.
+func nodeRange(node *yaml.Node) diag.Range {
+	start := diag.Pos{Line: node.Line, Column: node.Column}
+	if node.Kind != yaml.ScalarNode || node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return diag.Range{Start: start, End: start}
+	}
+
+	return diag.Range{
+		Start: start,
+		End:   diag.Pos{Line: node.Line, Column: node.Column + len(node.Value) - 1},
+	}
+}
+
+// keyNode finds the key node at the dotted key path.
+func keyNode(node *yaml.Node, path string) *yaml.Node {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	var key *yaml.Node
+	for _, segment := range strings.Split(path, ".") {
+		name, indexes, _ := strings.Cut(segment, "[")
+
+		key = nil
+		for i := 0; node.Kind == yaml.MappingNode && i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == name {
+				key, node = node.Content[i], node.Content[i+1]
+				break
+			}
+		}
+		if key == nil {
+			return nil
+		}
+
+		// step into any sequence indexes, for example `servers[0]`.
+		for _, index := range strings.Split(indexes, "[") {
+			if index == "" {
+				continue
+			}
+
+			i, err := strconv.Atoi(strings.TrimSuffix(index, "]"))
+			if err != nil || node.Kind != yaml.SequenceNode || i >= len(node.Content) {
+				return nil
+			}
+			node = node.Content[i]
+		}
+	}
+
+	return key
+}
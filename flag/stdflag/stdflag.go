@@ -1,8 +1,11 @@
 package stdflag
 
 import (
+	"errors"
 	"flag"
-	"os"
+	"fmt"
+	"io"
+	"strings"
 
 	"github.com/matthewhartstonge/configurator"
 )
@@ -11,8 +14,25 @@ var (
 	_ configurator.ConfigParser          = (*Flag)(nil)
 	_ configurator.ConfigFlagImplementer = (*Flag)(nil)
 	_ configurator.ConfigImplementer     = (*Flag)(nil)
+	_ configurator.BoolFlagReporter      = (*Flag)(nil)
 )
 
+// FlagSetImplementer is implemented by flag configs that define their flags on
+// the provided flag set, rather than the global flag.CommandLine.
+type FlagSetImplementer interface {
+	configurator.ConfigImplementer
+
+	// InitFlagSet is called to define the flags on the provided flag set.
+	InitFlagSet(fs *flag.FlagSet)
+}
+
+// New returns a flag configurator for a config that defines its flags on the
+// global flag.CommandLine. The flags are parsed with a flag set of the
+// configurator's own, so parsing doesn't mutate flag.CommandLine.
+//
+// Deprecated: flags can only be defined on flag.CommandLine once per process,
+// so configs can't be parsed by more than one Config, for example in parallel
+// tests. Implement FlagSetImplementer and use NewFlagSet instead.
 func New(config configurator.ConfigFlagImplementer) *Flag {
 	return &Flag{
 		ConfigType: configurator.ConfigType{
@@ -21,8 +41,24 @@ func New(config configurator.ConfigFlagImplementer) *Flag {
 	}
 }
 
+// NewFlagSet returns a flag configurator for a config that defines its flags
+// on the configurator's own flag set.
+func NewFlagSet(config FlagSetImplementer) *Flag {
+	return &Flag{
+		ConfigType: configurator.ConfigType{
+			Config: config,
+		},
+	}
+}
+
 type Flag struct {
 	configurator.ConfigType
+
+	// FlagSet is the flag set that flags are defined on. If nil, defaults to a
+	// new flag set for a FlagSetImplementer, otherwise flag.CommandLine.
+	// Arguments are always parsed with a copy of the flag set, so parsing
+	// neither exits, prints errors, nor mutates the flag set's state.
+	FlagSet *flag.FlagSet
 }
 
 func (f *Flag) Init() {
@@ -30,15 +66,152 @@ func (f *Flag) Init() {
 		return
 	}
 
-	if f.Config != nil {
-		f.Config.(configurator.ConfigFlagImplementer).Init()
+	switch config := f.Config.(type) {
+	case FlagSetImplementer:
+		if f.FlagSet == nil {
+			f.FlagSet = flag.NewFlagSet("", flag.ContinueOnError)
+		}
+		config.InitFlagSet(f.FlagSet)
+	case configurator.ConfigFlagImplementer:
+		config.Init()
 	}
 }
 
+// IsBoolFlag reports whether the named flag is a boolean flag, taking no
+// value.
+func (f *Flag) IsBoolFlag(name string) bool {
+	fs := f.FlagSet
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+
+	fl := fs.Lookup(name)
+	return fl != nil && isBoolFlag(fl)
+}
+
 func (f *Flag) Type() string {
 	return "stdflag configurator"
 }
 
-func (f *Flag) Parse(_ *configurator.Config) (string, error) {
-	return "args", flag.CommandLine.Parse(os.Args[1:])
+func (f *Flag) Parse(opts *configurator.Options) (string, error) {
+	src := f.FlagSet
+	if src == nil {
+		src = flag.CommandLine
+	}
+	if src.Name() == "" {
+		// name the flag set after the app for usage output.
+		src.Init(opts.AppName, src.ErrorHandling())
+	}
+
+	// flags are parsed with a copy of the flag set that neither prints nor
+	// exits, so that errors are reported once, as diagnostics.
+	fs := copyFlagSet(src)
+	args := opts.FlagArgs()
+	if name, ok := unknownFlag(fs, args); ok {
+		err := fmt.Errorf("flag provided but not defined: -%s", name)
+		if suggestion := configurator.DidYouMean("-"+name, flagNames(fs)); suggestion != "" {
+			err = fmt.Errorf("%w. %s", err, suggestion)
+		}
+
+		return "args", err
+	}
+
+	err := fs.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		printUsage(src)
+	}
+
+	return "args", err
+}
+
+// copyFlagSet returns a new flag set, defining each of the flags of the source
+// flag set, that continues on error and discards its output. Arguments are
+// parsed into the source's flag values without mutating the state of the
+// source flag set.
+func copyFlagSet(src *flag.FlagSet) *flag.FlagSet {
+	fs := flag.NewFlagSet(src.Name(), flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	src.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+
+	return fs
+}
+
+// unknownFlag returns the name of the first flag within the arguments that
+// isn't defined on the flag set, scanning the arguments as the flag package
+// parses them, stopping at the first positional argument or `--` terminator.
+func unknownFlag(fs *flag.FlagSet, args []string) (string, bool) {
+	for len(args) > 0 {
+		arg := args[0]
+		if len(arg) < 2 || arg[0] != '-' {
+			return "", false
+		}
+
+		name := arg[1:]
+		if name[0] == '-' {
+			name = name[1:]
+			if name == "" {
+				// `--` terminates the flags.
+				return "", false
+			}
+		}
+		if name[0] == '-' || name[0] == '=' {
+			// a bad flag syntax error is reported by the flag package.
+			return "", false
+		}
+
+		name, _, hasValue := strings.Cut(name, "=")
+		args = args[1:]
+
+		f := fs.Lookup(name)
+		if f == nil {
+			if name == "help" || name == "h" {
+				// help is always understood by the flag package.
+				return "", false
+			}
+
+			return name, true
+		}
+
+		if isBoolFlag(f) {
+			continue
+		}
+		if !hasValue && len(args) > 0 {
+			// step over the flag's value.
+			args = args[1:]
+		}
+	}
+
+	return "", false
+}
+
+// isBoolFlag reports whether the flag is a boolean flag, taking no value.
+func isBoolFlag(f *flag.Flag) bool {
+	boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && boolFlag.IsBoolFlag()
+}
+
+// flagNames returns the name of each flag defined on the flag set.
+func flagNames(fs *flag.FlagSet) []string {
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) {
+		names = append(names, "-"+f.Name)
+	})
+
+	return names
+}
+
+// printUsage prints the usage of the flag set, as the flag package does when
+// help is requested.
+func printUsage(fs *flag.FlagSet) {
+	switch {
+	case fs == flag.CommandLine:
+		flag.Usage()
+	case fs.Usage != nil:
+		fs.Usage()
+	default:
+		fmt.Fprintf(fs.Output(), "Usage of %s:\n", fs.Name())
+		fs.PrintDefaults()
+	}
 }
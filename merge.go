@@ -0,0 +1,421 @@
+package configurator
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// MergeTag is the struct tag used to map a config implementer's field onto a
+// field of the domain config when merging reflectively.
+//
+// The tag value is the dotted Go field path of the domain config field to
+// merge into, for example:
+//
+//	type FileConfig struct {
+//		MyApp struct {
+//			Port int `yaml:"port" configurator:"Server.Port"`
+//		} `yaml:"myapp"`
+//	}
+//
+// Untagged structs are walked so that nested fields can be tagged, while a tag
+// value of "-" skips the field entirely.
+const MergeTag = "configurator"
+
+// Merge reflectively merges the non-zero, MergeTag tagged fields of values
+// into the domain config. Domain must be a non-nil pointer to a struct.
+//
+// Merge is used as the fallback by ConfigType.Merge and Config.Parse for
+// config implementers that do not provide their own Merge method. Values are
+// assigned directly where the types allow it, otherwise converted, for example
+// an int field can be merged into a uint16 domain field, as long as the value
+// doesn't overflow the domain field.
+func Merge(domain any, values any) error {
+	if values == nil {
+		// Nothing to merge!
+		return nil
+	}
+
+	dst := reflect.ValueOf(domain)
+	if dst.Kind() != reflect.Pointer || dst.IsNil() || dst.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("domain config must be a non-nil pointer to a struct, got %T", domain)
+	}
+
+	src := reflect.ValueOf(values)
+	if src.Kind() == reflect.Pointer {
+		if src.IsNil() {
+			return nil
+		}
+		src = src.Elem()
+	}
+	if src.Kind() != reflect.Struct {
+		return fmt.Errorf("config values must be a struct or a pointer to a struct, got %T", values)
+	}
+
+	return mergeStruct(dst.Elem(), src)
+}
+
+// mergeStruct walks the fields of src, merging tagged fields into dst.
+func mergeStruct(dst, src reflect.Value) error {
+	srcType := src.Type()
+	for i := 0; i < srcType.NumField(); i++ {
+		field := srcType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		value := src.Field(i)
+		tag, ok := field.Tag.Lookup(MergeTag)
+		if tag == "-" {
+			continue
+		}
+
+		if !ok || tag == "" {
+			// walk untagged structs to find tagged nested fields.
+			if value.Kind() == reflect.Pointer {
+				if value.IsNil() {
+					continue
+				}
+				value = value.Elem()
+			}
+			if value.Kind() != reflect.Struct {
+				continue
+			}
+			if err := mergeStruct(dst, value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if value.IsZero() {
+			// Only merge values that have been set.
+			continue
+		}
+
+		target, err := fieldByPath(dst, tag)
+		if err != nil {
+			return fmt.Errorf("unable to merge field %s: %w", field.Name, err)
+		}
+
+		if err := assign(target, value); err != nil {
+			return fmt.Errorf("unable to merge field %s into %s: %w", field.Name, tag, err)
+		}
+	}
+
+	return nil
+}
+
+// mergeTags returns the domain field path each MergeTag tagged field of the
+// struct type merges into, keyed by the dotted Go field path of the tagged
+// field.
+func mergeTags(t reflect.Type) map[string]string {
+	tags := make(map[string]string)
+	collectMergeTags(tags, "", t, make(map[reflect.Type]bool))
+
+	return tags
+}
+
+func collectMergeTags(tags map[string]string, prefix string, t reflect.Type, seen map[reflect.Type]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		path := field.Name
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+
+		tag, ok := field.Tag.Lookup(MergeTag)
+		switch {
+		case tag == "-":
+			continue
+		case ok && tag != "":
+			tags[path] = tag
+		default:
+			collectMergeTags(tags, path, field.Type, seen)
+		}
+	}
+}
+
+// fieldByPath resolves a dotted field path against a struct, allocating any
+// nil struct pointers along the way.
+func fieldByPath(v reflect.Value, path string) (reflect.Value, error) {
+	for _, name := range strings.Split(path, ".") {
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("domain field path %q traverses non-struct type %s", path, v.Type())
+		}
+
+		field, ok := v.Type().FieldByName(name)
+		if !ok || !field.IsExported() {
+			return reflect.Value{}, fmt.Errorf("domain field %q not found in %s", name, v.Type())
+		}
+		v = v.FieldByIndex(field.Index)
+	}
+
+	return v, nil
+}
+
+// assign sets target to value, converting between compatible types.
+func assign(target, value reflect.Value) error {
+	if value.Kind() == reflect.Pointer && target.Kind() != reflect.Pointer {
+		value = value.Elem()
+	}
+
+	switch {
+	case value.Type().AssignableTo(target.Type()):
+		target.Set(value)
+	case target.Kind() == reflect.String && value.Kind() != reflect.String:
+		// reflect happily converts integers into runes, which is never what is
+		// wanted from config.
+		return fmt.Errorf("cannot convert %s to %s", value.Type(), target.Type())
+	case isNumber(value.Kind()) && isNumber(target.Kind()):
+		return assignNumber(target, value)
+	case value.Type().ConvertibleTo(target.Type()):
+		target.Set(value.Convert(target.Type()))
+	default:
+		return fmt.Errorf("cannot convert %s to %s", value.Type(), target.Type())
+	}
+
+	return nil
+}
+
+// assignNumber sets the numeric target to the numeric value, returning an
+// error rather than truncating values that can't be represented by the target
+// type, for example, 70000 can't be merged into a uint16.
+func assignNumber(target, value reflect.Value) error {
+	switch {
+	case isInt(target.Kind()):
+		var n int64
+		switch {
+		case isInt(value.Kind()):
+			n = value.Int()
+		case isUint(value.Kind()):
+			if value.Uint() > math.MaxInt64 {
+				return fmt.Errorf("value %d overflows %s", value.Uint(), target.Type())
+			}
+			n = int64(value.Uint())
+		default:
+			f := value.Float()
+			if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+				return fmt.Errorf("value %v can't be represented by %s", f, target.Type())
+			}
+			n = int64(f)
+		}
+		if target.OverflowInt(n) {
+			return fmt.Errorf("value %d overflows %s", n, target.Type())
+		}
+		target.SetInt(n)
+
+	case isUint(target.Kind()):
+		var n uint64
+		switch {
+		case isInt(value.Kind()):
+			if value.Int() < 0 {
+				return fmt.Errorf("negative value %d can't be represented by %s", value.Int(), target.Type())
+			}
+			n = uint64(value.Int())
+		case isUint(value.Kind()):
+			n = value.Uint()
+		default:
+			f := value.Float()
+			if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+				return fmt.Errorf("value %v can't be represented by %s", f, target.Type())
+			}
+			n = uint64(f)
+		}
+		if target.OverflowUint(n) {
+			return fmt.Errorf("value %d overflows %s", n, target.Type())
+		}
+		target.SetUint(n)
+
+	default:
+		var f float64
+		switch {
+		case isInt(value.Kind()):
+			f = float64(value.Int())
+		case isUint(value.Kind()):
+			f = float64(value.Uint())
+		default:
+			f = value.Float()
+		}
+		if target.OverflowFloat(f) {
+			return fmt.Errorf("value %v overflows %s", f, target.Type())
+		}
+		target.SetFloat(f)
+	}
+
+	return nil
+}
+
+// isNumber reports whether the kind is an integer or floating point number.
+func isNumber(kind reflect.Kind) bool {
+	return isInt(kind) || isUint(kind) || kind == reflect.Float32 || kind == reflect.Float64
+}
+
+// isInt reports whether the kind is a signed integer.
+func isInt(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+
+	return false
+}
+
+// isUint reports whether the kind is an unsigned integer.
+func isUint(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+
+	return false
+}
+
+// hasMergeTags reports whether any field of the struct type, or of the
+// untagged structs it contains, is tagged with MergeTag, and so can be merged
+// reflectively.
+func hasMergeTags(t reflect.Type) bool {
+	return hasMergeTagsSeen(t, make(map[reflect.Type]bool))
+}
+
+func hasMergeTagsSeen(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, ok := field.Tag.Lookup(MergeTag)
+		switch {
+		case tag == "-":
+			continue
+		case ok && tag != "":
+			return true
+		case hasMergeTagsSeen(field.Type, seen):
+			return true
+		}
+	}
+
+	return false
+}
+
+// resetValues zeroes the provided values if they point to a struct.
+func resetValues(v any) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return
+	}
+
+	rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+}
+
+// copyValues returns a deep copy of the provided values if they point to a
+// struct, otherwise the values are returned as is.
+func copyValues(v any) any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return v
+	}
+
+	return deepCopy(rv).Interface()
+}
+
+// deepCopyOf returns a deep copy of v, see deepCopy.
+func deepCopyOf[T any](v T) T {
+	return deepCopy(reflect.ValueOf(&v).Elem()).Interface().(T)
+}
+
+// deepCopy returns a copy of the value that shares no maps, slices or pointers
+// with the original, so that values merged in place into one, for example
+// `domain.Tags[key] = value`, aren't seen by the other. Unexported struct
+// fields are copied shallowly.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+
+		cp := reflect.New(v.Type().Elem())
+		cp.Elem().Set(deepCopy(v.Elem()))
+		return cp
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(deepCopy(v.Elem()))
+		return cp
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		cp := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			cp.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return cp
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return cp
+
+	case reflect.Array:
+		cp := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return cp
+
+	case reflect.Struct:
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				cp.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return cp
+
+	default:
+		return v
+	}
+}
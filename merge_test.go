@@ -0,0 +1,202 @@
+package configurator
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/matthewhartstonge/configurator/diag"
+)
+
+func TestAssign(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+
+	tests := []struct {
+		name    string
+		target  any
+		value   any
+		want    any
+		wantErr string
+	}{
+		{name: "same type", target: int(0), value: int(42), want: int(42)},
+		{name: "int into uint16", target: uint16(0), value: int(8080), want: uint16(8080)},
+		{name: "int overflows uint16", target: uint16(0), value: int(70000), wantErr: "value 70000 overflows uint16"},
+		{name: "negative int into uint", target: uint(0), value: int(-1), wantErr: "negative value -1 can't be represented by uint"},
+		{name: "int overflows int8", target: int8(0), value: int(128), wantErr: "value 128 overflows int8"},
+		{name: "negative int into int8", target: int8(0), value: int(-128), want: int8(-128)},
+		{name: "uint into int64", target: int64(0), value: uint64(42), want: int64(42)},
+		{name: "uint overflows int64", target: int64(0), value: uint64(math.MaxUint64), wantErr: "overflows int64"},
+		{name: "whole float into int", target: int(0), value: float64(3), want: int(3)},
+		{name: "fractional float into int", target: int(0), value: float64(3.5), wantErr: "value 3.5 can't be represented by int"},
+		{name: "negative float into uint", target: uint(0), value: float64(-1), wantErr: "value -1 can't be represented by uint"},
+		{name: "int into float32", target: float32(0), value: int(3), want: float32(3)},
+		{name: "float64 overflows float32", target: float32(0), value: math.MaxFloat64, wantErr: "overflows float32"},
+		{name: "string into string type", target: testString(""), value: "value", want: testString("value")},
+		{name: "int into string", target: "", value: int(65), wantErr: "cannot convert int to string"},
+		{name: "string into int", target: int(0), value: "42", wantErr: "cannot convert string to int"},
+		{name: "pointer value into value", target: int(0), value: intPtr(42), want: int(42)},
+		{name: "pointer value into pointer", target: (*int)(nil), value: intPtr(42), want: intPtr(42)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := reflect.New(reflect.TypeOf(tt.target)).Elem()
+			err := assign(target, reflect.ValueOf(tt.value))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("assign() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("assign() error = %v", err)
+			}
+
+			if got := target.Interface(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("assign() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+type testString string
+
+type mergeDomain struct {
+	Name   string
+	Port   uint16
+	Server *struct {
+		Timeout float64
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name    string
+		domain  any
+		values  any
+		want    mergeDomain
+		wantErr string
+	}{
+		{
+			name:   "nil values",
+			domain: &mergeDomain{Name: "default"},
+			values: nil,
+			want:   mergeDomain{Name: "default"},
+		},
+		{
+			name:   "tagged fields",
+			domain: &mergeDomain{Name: "default"},
+			values: &struct {
+				Name string `configurator:"Name"`
+				Port int    `configurator:"Port"`
+			}{Name: "app", Port: 80},
+			want: mergeDomain{Name: "app", Port: 80},
+		},
+		{
+			name:   "zero values aren't merged",
+			domain: &mergeDomain{Name: "default", Port: 80},
+			values: &struct {
+				Name string `configurator:"Name"`
+				Port int    `configurator:"Port"`
+			}{},
+			want: mergeDomain{Name: "default", Port: 80},
+		},
+		{
+			name:   "untagged structs are walked",
+			domain: &mergeDomain{},
+			values: &struct {
+				App struct {
+					Port int `configurator:"Port"`
+				}
+			}{App: struct {
+				Port int `configurator:"Port"`
+			}{Port: 80}},
+			want: mergeDomain{Port: 80},
+		},
+		{
+			name:   "nil struct pointers are allocated",
+			domain: &mergeDomain{},
+			values: &struct {
+				Timeout int `configurator:"Server.Timeout"`
+			}{Timeout: 30},
+			want: mergeDomain{Server: &struct{ Timeout float64 }{Timeout: 30}},
+		},
+		{
+			name:   "skipped fields",
+			domain: &mergeDomain{},
+			values: &struct {
+				Name string `configurator:"-"`
+			}{Name: "app"},
+			want: mergeDomain{},
+		},
+		{
+			name:   "overflow",
+			domain: &mergeDomain{},
+			values: &struct {
+				Port int `configurator:"Port"`
+			}{Port: 70000},
+			wantErr: "unable to merge field Port into Port: value 70000 overflows uint16",
+		},
+		{
+			name:   "unknown domain field",
+			domain: &mergeDomain{},
+			values: &struct {
+				Port int `configurator:"Server.Port"`
+			}{Port: 80},
+			wantErr: `domain field "Port" not found`,
+		},
+		{
+			name:    "non-pointer domain",
+			domain:  mergeDomain{},
+			values:  &struct{}{},
+			wantErr: "domain config must be a non-nil pointer to a struct",
+		},
+		{
+			name:    "non-struct values",
+			domain:  &mergeDomain{},
+			values:  42,
+			wantErr: "config values must be a struct or a pointer to a struct",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Merge(tt.domain, tt.values)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Merge() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Merge() error = %v", err)
+			}
+
+			if got := *tt.domain.(*mergeDomain); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Merge() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+type portConfig struct {
+	Port int `configurator:"Port"`
+}
+
+func (p *portConfig) Validate(diag.Component) *diag.Diagnostics {
+	return nil
+}
+
+func TestConfigTypeMergeE(t *testing.T) {
+	config := &ConfigType{Config: &portConfig{Port: 70000}}
+
+	domain := &mergeDomain{Port: 80}
+	merged, err := config.MergeE(domain)
+	if err == nil || !strings.Contains(err.Error(), "overflows uint16") {
+		t.Errorf("MergeE() error = %v, want an overflow", err)
+	}
+	if merged != domain || domain.Port != 80 {
+		t.Errorf("MergeE() = %+v, want the domain config left untouched", merged)
+	}
+}
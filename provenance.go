@@ -0,0 +1,157 @@
+package configurator
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/matthewhartstonge/configurator/diag"
+)
+
+// Provenance records which source set the final value of a domain config
+// field.
+type Provenance struct {
+	// Field is the dotted Go field path of the domain config field, for
+	// example "MyApp.Port".
+	Field string
+	// Component specifies which component set the value.
+	Component diag.Component
+	// Path specifies either the file path, or environment variable the value
+	// came from. If the parser can't report which environment variable set
+	// the value, the environment variable prefix is used instead.
+	Path string
+	// Value holds the value that was set.
+	Value any
+	// Overridden holds the values that were previously set, in the order they
+	// were set.
+	Overridden []Provenance
+}
+
+// Explain returns the provenance of the given domain config field. Fields are
+// addressed by their dotted Go field path and matched case-insensitively, so
+// both "MyApp.Port" and "myapp.port" explain the same field.
+//
+// Explain isn't safe to call while watching, use Watcher.Explain instead.
+func (c *Config[T]) Explain(field string) (Provenance, bool) {
+	p, ok := c.provenance[strings.ToLower(field)]
+	if !ok {
+		return Provenance{}, false
+	}
+
+	return *p, true
+}
+
+// resetProvenance records the current domain config values as defaults.
+func (c *Config[T]) resetProvenance() {
+	c.provenance = make(map[string]*Provenance)
+	c.recordProvenance(diag.ComponentDefault, "", nil, nil)
+}
+
+// recordProvenance records every domain config field whose value differs from
+// the values before the merge took place. Fields are recorded as set from the
+// path, unless sources reports the source key that set the field, keyed by
+// lower cased domain field path.
+func (c *Config[T]) recordProvenance(component diag.Component, path string, before map[string]any, sources map[string]string) {
+	for field, value := range flattenFields(c.Domain) {
+		if prev, ok := before[field]; ok && reflect.DeepEqual(prev, value) {
+			continue
+		}
+
+		key := strings.ToLower(field)
+		p := &Provenance{
+			Field:     field,
+			Component: component,
+			Path:      path,
+			Value:     value,
+		}
+		if source, ok := sources[key]; ok {
+			p.Path = source
+		}
+		if prev, ok := c.provenance[key]; ok {
+			p.Overridden = append(prev.Overridden, Provenance{
+				Field:     prev.Field,
+				Component: prev.Component,
+				Path:      prev.Path,
+				Value:     prev.Value,
+			})
+		}
+
+		c.provenance[key] = p
+	}
+}
+
+// fieldSources returns the source key that set each domain config field, keyed
+// by lower cased domain field path, if the configurer reports the source of
+// each of its fields. Fields are mapped onto the domain field they are tagged
+// with, otherwise onto the domain field with the same path.
+func (c *Config[T]) fieldSources(configurer ConfigTypeable) map[string]string {
+	sourcer, ok := configurer.(FieldSourcer)
+	if !ok {
+		return nil
+	}
+
+	fieldSources := sourcer.FieldSources()
+	if len(fieldSources) == 0 {
+		return nil
+	}
+
+	tags := mergeTags(reflect.TypeOf(configurer.Values()))
+	sources := make(map[string]string, len(fieldSources))
+	for field, source := range fieldSources {
+		domainField := field
+		if tag, ok := tags[field]; ok {
+			domainField = tag
+		}
+		sources[strings.ToLower(domainField)] = source
+	}
+
+	return sources
+}
+
+// flattenFields returns the leaf values of a struct keyed by dotted Go field
+// path.
+func flattenFields(v any) map[string]any {
+	fields := make(map[string]any)
+	flattenValue(fields, "", reflect.ValueOf(v))
+
+	return fields
+}
+
+func flattenValue(fields map[string]any, prefix string, v reflect.Value) {
+	if v.Kind() == reflect.Pointer && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct || !hasExportedFields(v.Type()) {
+		// leaves are copied, as maps and slices merged into in place would
+		// otherwise always equal the values before the merge.
+		if prefix != "" && v.IsValid() {
+			fields[prefix] = deepCopy(v).Interface()
+		}
+		return
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		flattenValue(fields, name, v.Field(i))
+	}
+}
+
+// hasExportedFields reports whether a struct exposes any fields, as opaque
+// structs, such as time.Time, are treated as a singular value.
+func hasExportedFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+
+	return false
+}
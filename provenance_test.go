@@ -0,0 +1,85 @@
+package configurator
+
+import (
+	"encoding/json"
+	"maps"
+	"testing"
+	"testing/fstest"
+
+	"github.com/matthewhartstonge/configurator/diag"
+)
+
+// testFile parses JSON config files.
+type testFile struct {
+	ConfigFileType
+}
+
+func newTestFile(config ConfigImplementer) *testFile {
+	return &testFile{NewConfigFileType(config, []string{"json"}, json.Unmarshal)}
+}
+
+type tagsDomain struct {
+	Tags map[string]string
+}
+
+type tagsConfig struct {
+	Tags map[string]string `json:"tags"`
+}
+
+func (t *tagsConfig) Validate(diag.Component) *diag.Diagnostics {
+	return nil
+}
+
+// Merge merges the tags in place into the domain config's tags.
+func (t *tagsConfig) Merge(domain *tagsDomain) {
+	maps.Copy(domain.Tags, t.Tags)
+}
+
+func TestExplainMapMergedInPlace(t *testing.T) {
+	config := &Config[tagsDomain]{
+		Options: Options{
+			FS: fstest.MapFS{
+				"etc/app/a.json": {Data: []byte(`{"tags": {"team": "platform"}}`)},
+				"etc/app/b.json": {Data: []byte(`{"tags": {"tier": "gold"}}`)},
+			},
+			Args:            []string{"app"},
+			ConfigFilePaths: []string{"/etc/app/a.json", "/etc/app/b.json"},
+		},
+		Domain: &tagsDomain{Tags: map[string]string{"env": "dev"}},
+		File:   []ConfigFileTypeable{newTestFile(&tagsConfig{})},
+	}
+
+	if _, diags := config.Parse(); diags.HasError {
+		t.Fatalf("Parse() reported errors: %v", diags.Err())
+	}
+
+	want := map[string]string{"env": "dev", "team": "platform", "tier": "gold"}
+	if !maps.Equal(config.Domain.Tags, want) {
+		t.Fatalf("Domain.Tags = %v, want %v", config.Domain.Tags, want)
+	}
+
+	p, ok := config.Explain("tags")
+	if !ok {
+		t.Fatal("Explain(tags) found no provenance")
+	}
+	if p.Component != diag.ComponentFlagFile || p.Path != "/etc/app/b.json" {
+		t.Errorf("Explain(tags) = %s %q, want %s %q", p.Component, p.Path, diag.ComponentFlagFile, "/etc/app/b.json")
+	}
+	if got := p.Value.(map[string]string); !maps.Equal(got, want) {
+		t.Errorf("Explain(tags).Value = %v, want %v", got, want)
+	}
+	if len(p.Overridden) != 2 {
+		t.Fatalf("Explain(tags).Overridden = %v, want the default and a.json", p.Overridden)
+	}
+	if got := p.Overridden[0].Value.(map[string]string); !maps.Equal(got, map[string]string{"env": "dev"}) {
+		t.Errorf("Explain(tags).Overridden[0].Value = %v, want the default tags", got)
+	}
+
+	parsed := config.Values()
+	if len(parsed) != 2 {
+		t.Fatalf("Values() = %d parsed configs, want 2", len(parsed))
+	}
+	if got := parsed[0].Domain.Tags; !maps.Equal(got, map[string]string{"env": "dev", "team": "platform"}) {
+		t.Errorf("Values()[0].Domain.Tags = %v, want the tags as merged by a.json", got)
+	}
+}
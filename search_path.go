@@ -0,0 +1,169 @@
+package configurator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/matthewhartstonge/configurator/diag"
+)
+
+// PathStrategy returns the directories, or config file paths, to search for
+// config files, recording diagnostics for each path considered.
+type PathStrategy func(diags *diag.Diagnostics, component diag.Component, opts *Options) ([]string, *diag.Diagnostics)
+
+// SearchPath registers a path strategy to search for a file component's config
+// files.
+type SearchPath struct {
+	// Name describes the search path in diagnostics.
+	Name string
+	// Component specifies the file component the paths are searched for,
+	// either diag.ComponentGlobalFile, diag.ComponentLocalFile or
+	// diag.ComponentFlagFile.
+	Component diag.Component
+	// Order specifies the order in which search paths are searched, from
+	// lowest to highest. The built-in search paths have an order of 0, so a
+	// negative order searches before, and a positive order after, the built-in
+	// paths. Search paths with the same order are searched in the order they
+	// were added.
+	//
+	// By default, the first config file found is processed, so earlier search
+	// paths take precedence. If layered, later search paths take precedence.
+	Order int
+	// Strategy returns the paths to search.
+	Strategy PathStrategy
+}
+
+// AddSearchPath registers a path strategy to search for the component's config
+// files.
+func (o *Options) AddSearchPath(name string, component diag.Component, order int, strategy PathStrategy) {
+	o.SearchPaths = append(o.SearchPaths, SearchPath{
+		Name:      name,
+		Component: component,
+		Order:     order,
+		Strategy:  strategy,
+	})
+}
+
+// AppDirStrategy returns a path strategy that searches the application's
+// directory within the provided directory, for example, an AppDirStrategy for
+// "/opt/org" searches "/opt/org/<AppName>".
+func AppDirStrategy(dir string) PathStrategy {
+	return func(diags *diag.Diagnostics, component diag.Component, opts *Options) ([]string, *diag.Diagnostics) {
+		fp := configFP(opts, filepath.Clean(dir))
+		diags.FromComponent(component, dir).
+			Code(CodePathAdded).
+			Trace("Application Directory Added", fp)
+
+		return []string{fp}, diags
+	}
+}
+
+// EnvDirStrategy returns a path strategy that searches the directory specified
+// by the named environment variable, for example, "APP_CONFIG_DIR".
+func EnvDirStrategy(key string) PathStrategy {
+	return func(diags *diag.Diagnostics, component diag.Component, opts *Options) ([]string, *diag.Diagnostics) {
+		dir, ok := opts.Environment.LookupEnv(key)
+		if !ok || dir == "" {
+			diags.FromComponent(component, key).
+				Code(CodePathUnavailable).
+				Trace("Config Directory Environment Variable Not Set",
+					fmt.Sprintf("Set %s to search for config files in a specific directory", key))
+			return nil, diags
+		}
+
+		diags.FromComponent(component, key).Code(CodePathAdded).Trace("Config Directory Added", dir)
+		return []string{filepath.Clean(dir)}, diags
+	}
+}
+
+// UpwardStrategy returns a path strategy that walks up the parent directories
+// of the working directory, searching each directory for config files as well
+// as a `.<appname>rc` file, for example, `.exampleapprc`.
+//
+// The walk stops at the first directory containing any of the sentinels, for
+// example ".git", before reaching the user's home directory, or at the
+// filesystem root. Nearer directories are searched before parent
+// directories, and within a directory, config files are searched before the
+// rc file.
+func UpwardStrategy(sentinels ...string) PathStrategy {
+	return func(diags *diag.Diagnostics, component diag.Component, opts *Options) ([]string, *diag.Diagnostics) {
+		dir, err := opts.Environment.Getwd()
+		if err != nil {
+			diags.FromComponent(component, dir).
+				Cause(err).
+				Code(CodePathUnavailable).
+				Trace("Unable to obtain path to current working directory",
+					"Unable to search parent directories for config files as "+err.Error())
+			return nil, diags
+		}
+
+		home, _ := opts.Environment.UserHomeDir()
+		rcFile := "." + strings.ToLower(opts.AppName) + "rc"
+
+		var paths []string
+		for dir = filepath.Clean(dir); ; dir = filepath.Dir(dir) {
+			if home != "" && dir == filepath.Clean(home) {
+				diags.FromComponent(component, dir).
+					Code(CodeSearchStopped).
+					Trace("Stopped Searching Parent Directories",
+						"Reached the user's home directory")
+				break
+			}
+
+			diags.FromComponent(component, dir).Code(CodePathAdded).Trace("Parent Directory Added", dir)
+			paths = append(paths, dir)
+
+			rcPath := filepath.Join(dir, rcFile)
+			if _, err := opts.StatFile(rcPath); err == nil {
+				diags.FromComponent(component, dir).Code(CodePathAdded).Trace("RC File Added", rcPath)
+				paths = append(paths, rcPath)
+			}
+
+			if sentinel, ok := findSentinel(opts, dir, sentinels); ok {
+				diags.FromComponent(component, dir).
+					Code(CodeSearchStopped).
+					Trace("Stopped Searching Parent Directories",
+						fmt.Sprintf("Found sentinel %s", sentinel))
+				break
+			}
+
+			if parent := filepath.Dir(dir); parent == dir {
+				diags.FromComponent(component, dir).
+					Code(CodeSearchStopped).
+					Trace("Stopped Searching Parent Directories",
+						"Reached the filesystem root")
+				break
+			}
+		}
+
+		return paths, diags
+	}
+}
+
+// findSentinel returns the first sentinel found within the directory.
+func findSentinel(opts *Options, dir string, sentinels []string) (string, bool) {
+	for _, sentinel := range sentinels {
+		if _, err := opts.StatFile(filepath.Join(dir, sentinel)); err == nil {
+			return sentinel, true
+		}
+	}
+
+	return "", false
+}
+
+// dedupePaths removes duplicate paths, keeping the first occurrence of each
+// path, where it is first searched.
+func dedupePaths(paths []string) []string {
+	seen := make(map[string]bool, len(paths))
+	deduped := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		deduped = append(deduped, path)
+	}
+
+	return deduped
+}
@@ -0,0 +1,61 @@
+package configurator
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+var (
+	// tomlTableRegexp matches TOML table headers, such as [server] or
+	// [[servers]].
+	tomlTableRegexp = regexp.MustCompile(`^\[\[?\s*[\w."' -]+\s*\]\]?\s*(#.*)?$`)
+	// hclBlockRegexp matches the opening of HCL blocks, such as
+	// app "name" {.
+	hclBlockRegexp = regexp.MustCompile(`^[\w-]+(\s+("[^"]*"|[\w-]+))*\s*\{\s*$`)
+)
+
+// sniffFileType makes a best effort guess as to the file type of the provided
+// config, returning one of "json", "toml", "hcl" or "yaml". YAML is assumed if
+// nothing more specific can be determined.
+func sniffFileType(data []byte) string {
+	var hasAssignment bool
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "{"):
+			return "json"
+		case tomlTableRegexp.MatchString(line):
+			return "toml"
+		case strings.HasPrefix(line, "["):
+			return "json"
+		case hclBlockRegexp.MatchString(line):
+			return "hcl"
+		case strings.HasPrefix(line, "---"):
+			return "yaml"
+		}
+
+		if key, _, ok := strings.Cut(line, "="); ok && !strings.Contains(key, ":") {
+			// both TOML and HCL use `key = value` assignments, so keep looking
+			// for a table or block to tell them apart.
+			hasAssignment = true
+			continue
+		}
+		if !hasAssignment {
+			return "yaml"
+		}
+	}
+
+	if hasAssignment {
+		return "toml"
+	}
+
+	return "yaml"
+}
@@ -0,0 +1,46 @@
+package configurator
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// StdinPath is the config file path used to read a config file from stdin,
+// for example `-config-file -`.
+const StdinPath = "-"
+
+// readStdin reads the config file from stdin, caching the content so that it
+// can be stat'ed and parsed, and re-parsed on reload.
+func (o *Options) readStdin() ([]byte, error) {
+	if o.stdinRead {
+		return o.stdin, nil
+	}
+
+	r := o.Stdin
+	if r == nil {
+		r = os.Stdin
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	o.stdin, o.stdinRead = data, true
+
+	return data, nil
+}
+
+// stdinFileInfo describes the config file read from stdin.
+type stdinFileInfo struct {
+	size int64
+}
+
+func (i stdinFileInfo) Name() string       { return StdinPath }
+func (i stdinFileInfo) Size() int64        { return i.size }
+func (i stdinFileInfo) Mode() fs.FileMode  { return 0 }
+func (i stdinFileInfo) ModTime() time.Time { return time.Time{} }
+func (i stdinFileInfo) IsDir() bool        { return false }
+func (i stdinFileInfo) Sys() any           { return nil }
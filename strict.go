@@ -0,0 +1,296 @@
+package configurator
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/matthewhartstonge/configurator/diag"
+)
+
+// StrictMode configures how keys found in a config file, that are unknown to
+// the config implementer, are reported.
+type StrictMode int
+
+const (
+	// StrictIgnore silently ignores unknown keys.
+	StrictIgnore StrictMode = iota
+	// StrictWarn reports a warning for each unknown key, while still merging
+	// the config file.
+	StrictWarn
+	// StrictError reports an error for each unknown key, rejecting the config
+	// file.
+	StrictError
+)
+
+// UnknownKeyFinder returns each key found in the config file data that is
+// unknown to the config implementer v.
+type UnknownKeyFinder func(data []byte, v any) ([]UnknownKey, error)
+
+// UnknownKey describes a key unknown to the config implementer.
+type UnknownKey struct {
+	// Path is the dotted path to the unknown key.
+	Path string
+	// Suggestions holds the closest known keys, see Suggest.
+	Suggestions []string
+	// Range optionally locates the unknown key within the config file.
+	Range *diag.Range
+}
+
+// Report reports each of the unknown keys found at the path based on the
+// strict mode, returning false if the config has been rejected.
+func (m StrictMode) Report(diags *diag.Diagnostics, component diag.Component, path string, unknown []UnknownKey) bool {
+	for _, key := range unknown {
+		detail := fmt.Sprintf("The key %q is not supported by the config", key.Path)
+		switch m {
+		case StrictWarn:
+			detail += ", so has been ignored."
+		case StrictError:
+			detail += ", so the config has been rejected."
+		default:
+			continue
+		}
+
+		if len(key.Suggestions) > 0 {
+			detail += " " + didYouMean(key.Suggestions)
+		}
+
+		builder := diags.FromComponent(component, path).Code(CodeUnknownKey)
+		if key.Range != nil {
+			builder = builder.At(*key.Range)
+		}
+
+		if m == StrictError {
+			builder.Error("Unknown Config Key", detail)
+		} else {
+			builder.Warn("Unknown Config Key", detail)
+		}
+	}
+
+	return len(unknown) == 0 || m != StrictError
+}
+
+// KeyFormat describes how a file format matches the keys of a config file onto
+// the fields of a struct, so that unknown keys are found the same way the
+// format's decoder ignores them.
+type KeyFormat struct {
+	// Tag is the struct tag naming the key of each field, for example "json".
+	Tag string
+	// CaseSensitive matches keys exactly, rather than case-insensitively.
+	CaseSensitive bool
+	// ExplicitInline only matches the fields of embedded structs as if they
+	// were part of the parent struct if tagged `inline`, otherwise embedded
+	// structs are matched as a regular field.
+	ExplicitInline bool
+	// FieldName returns the key of fields without a name given in the struct
+	// tag. If nil, the field name is used.
+	FieldName func(name string) string
+}
+
+var (
+	// JSONKeys matches keys as encoding/json does.
+	JSONKeys = KeyFormat{Tag: "json"}
+	// TOMLKeys matches keys as the TOML decoder does.
+	TOMLKeys = KeyFormat{Tag: "toml"}
+	// YAMLKeys matches keys as gopkg.in/yaml.v3 does, exactly, defaulting to
+	// the lower-cased field name, and only inlining structs tagged `inline`.
+	YAMLKeys = KeyFormat{
+		Tag:            "yaml",
+		CaseSensitive:  true,
+		ExplicitInline: true,
+		FieldName:      strings.ToLower,
+	}
+)
+
+// UnknownKeys returns each key within the decoded values that doesn't map onto
+// a field of v, sorted within each level of nesting.
+// Fields are matched by the name given in the format's struct tag, otherwise
+// by the field name. Embedded structs, and fields tagged `inline`, are matched
+// as if their fields were part of the parent struct, as configured by the
+// format.
+//
+// UnknownKeys enables file type providers to implement an UnknownKeyFinder for
+// formats that decode into a generic map, for example:
+//
+//	var values map[string]any
+//	if err := json.Unmarshal(data, &values); err != nil {
+//		return nil, err
+//	}
+//
+//	return configurator.UnknownKeys(values, v, configurator.JSONKeys), nil
+func UnknownKeys(values map[string]any, v any, format KeyFormat) []UnknownKey {
+	var unknown []UnknownKey
+	unknownKeys(&unknown, "", values, reflect.TypeOf(v), format)
+
+	return unknown
+}
+
+// unknownKeys walks the decoded values, collecting keys without a matching
+// struct field.
+func unknownKeys(unknown *[]UnknownKey, prefix string, values map[string]any, t reflect.Type, format KeyFormat) {
+	t = indirectType(t)
+	if t == nil || t.Kind() != reflect.Struct {
+		// maps and interfaces accept any key.
+		return
+	}
+
+	fields := make(map[string]reflect.Type)
+	var names []string
+	structKeys(fields, &names, t, format)
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		fieldType, ok := fields[format.matchKey(key)]
+		if !ok {
+			*unknown = append(*unknown, UnknownKey{
+				Path:        path,
+				Suggestions: Suggest(key, names),
+			})
+			continue
+		}
+
+		unknownValueKeys(unknown, path, values[key], fieldType, format)
+	}
+}
+
+// unknownValueKeys walks nested tables and arrays of tables.
+func unknownValueKeys(unknown *[]UnknownKey, path string, value any, t reflect.Type, format KeyFormat) {
+	switch value := value.(type) {
+	case map[string]any:
+		unknownKeys(unknown, path, value, t, format)
+	case []any:
+		t = indirectType(t)
+		if t == nil || (t.Kind() != reflect.Slice && t.Kind() != reflect.Array) {
+			return
+		}
+
+		for i, elem := range value {
+			unknownValueKeys(unknown, fmt.Sprintf("%s[%d]", path, i), elem, t.Elem(), format)
+		}
+	}
+}
+
+// structKeys collects the matchable key, and the name, of each exported field
+// of the struct type.
+func structKeys(fields map[string]reflect.Type, names *[]string, t reflect.Type, format KeyFormat) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, opts, _ := strings.Cut(field.Tag.Get(format.Tag), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		if embedded, ok := format.inlined(field, name, opts); ok {
+			structKeys(fields, names, embedded, format)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+			if format.FieldName != nil {
+				name = format.FieldName(name)
+			}
+		}
+
+		fields[format.matchKey(name)] = field.Type
+		*names = append(*names, name)
+	}
+}
+
+// KeyPath returns the dotted path of the key within a config file of the
+// format that decodes into the field at the dotted Go field path of v, for
+// example, the field "MyApp.Port" is keyed "myapp.port" in YAML, reporting
+// whether the field was found.
+func (f KeyFormat) KeyPath(v any, field string) (string, bool) {
+	t := reflect.TypeOf(v)
+	var keys []string
+	for _, name := range strings.Split(field, ".") {
+		key, fieldType, ok := f.fieldKey(indirectType(t), name)
+		if !ok {
+			return "", false
+		}
+
+		keys = append(keys, key)
+		t = fieldType
+	}
+
+	return strings.Join(keys, "."), true
+}
+
+// fieldKey returns the key, and the type, of the named field of the struct
+// type, searching the fields of inlined structs.
+func (f KeyFormat) fieldKey(t reflect.Type, fieldName string) (string, reflect.Type, bool) {
+	if t == nil || t.Kind() != reflect.Struct {
+		return "", nil, false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, opts, _ := strings.Cut(field.Tag.Get(f.Tag), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		if embedded, ok := f.inlined(field, name, opts); ok {
+			if key, fieldType, ok := f.fieldKey(embedded, fieldName); ok {
+				return key, fieldType, true
+			}
+			continue
+		}
+
+		if !field.IsExported() || !strings.EqualFold(field.Name, fieldName) {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+			if f.FieldName != nil {
+				name = f.FieldName(name)
+			}
+		}
+
+		return name, field.Type, true
+	}
+
+	return "", nil, false
+}
+
+// inlined returns the struct type of the field if the format matches its
+// fields as if they were part of the parent struct.
+func (f KeyFormat) inlined(field reflect.StructField, name, opts string) (reflect.Type, bool) {
+	inline := slices.Contains(strings.Split(opts, ","), "inline")
+	if !(field.Anonymous && name == "" && !f.ExplicitInline) && !inline {
+		return nil, false
+	}
+
+	embedded := indirectType(field.Type)
+	return embedded, embedded.Kind() == reflect.Struct
+}
+
+// matchKey returns the key as matched against struct fields.
+func (f KeyFormat) matchKey(key string) string {
+	if f.CaseSensitive {
+		return key
+	}
+
+	return strings.ToLower(key)
+}
+
+// indirectType dereferences pointer types.
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
@@ -0,0 +1,78 @@
+package configurator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Suggest returns the candidates closest to the provided name by edit
+// distance, ignoring case, to suggest in place of an unknown name. Only
+// candidates close enough to plausibly be a typo are returned.
+func Suggest(name string, candidates []string) []string {
+	maxDistance := len(name)/3 + 1
+
+	var suggestions []string
+	best := maxDistance + 1
+	for _, candidate := range candidates {
+		distance := editDistance(strings.ToLower(name), strings.ToLower(candidate))
+		switch {
+		case distance > maxDistance || distance > best:
+			continue
+		case distance < best:
+			best, suggestions = distance, nil
+		}
+
+		if !slices.Contains(suggestions, candidate) {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+	slices.Sort(suggestions)
+
+	return suggestions
+}
+
+// DidYouMean returns a sentence suggesting the candidates closest to the
+// provided name, or an empty string if there are no close candidates.
+func DidYouMean(name string, candidates []string) string {
+	return didYouMean(Suggest(name, candidates))
+}
+
+// didYouMean returns a sentence suggesting the provided suggestions.
+func didYouMean(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+
+	quoted := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		quoted[i] = fmt.Sprintf("%q", suggestion)
+	}
+
+	return fmt.Sprintf("Did you mean %s?", strings.Join(quoted, " or "))
+}
+
+// editDistance computes the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
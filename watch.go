@@ -0,0 +1,223 @@
+package configurator
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/matthewhartstonge/configurator/diag"
+)
+
+// DEFAULT_WATCH_INTERVAL is the polling interval used by Watch if none is
+// provided.
+const DEFAULT_WATCH_INTERVAL = 5 * time.Second
+
+// Subscriber is notified with the newly parsed domain config and the
+// diagnostics produced when parsing it.
+type Subscriber[T any] func(domain *T, diags *diag.Diagnostics)
+
+// Watcher polls every config file path that was stat'ed while parsing, and
+// re-parses the configuration when any of the files are created, modified or
+// removed.
+//
+// If re-parsing reports an error, the previously parsed domain config is
+// kept, but subscribers are still notified with the diagnostics so that the
+// failure can be reported.
+type Watcher[T any] struct {
+	config   *Config[T]
+	interval time.Duration
+
+	mu          sync.Mutex
+	subscribers map[int]Subscriber[T]
+	nextID      int
+	files       map[string]fileState
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// fileState captures the observable state of a watched file.
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+// Watch starts watching the config files discovered by the last call to
+// Parse, polling for changes at the given interval until the context is
+// cancelled or the watcher is stopped.
+//
+// Once watching, reloads swap the domain config, parsed values, provenance and
+// options concurrently, so must only be read from subscribers, or via
+// Watcher.Domain, Watcher.Values and Watcher.Explain, rather than via the
+// Config directly.
+func (c *Config[T]) Watch(ctx context.Context, interval time.Duration) *Watcher[T] {
+	if interval <= 0 {
+		interval = DEFAULT_WATCH_INTERVAL
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w := &Watcher[T]{
+		config:      c,
+		interval:    interval,
+		subscribers: make(map[int]Subscriber[T]),
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+	w.files = statFiles(&c.Options, c.statted)
+
+	go w.run(ctx)
+
+	return w
+}
+
+// Subscribe registers a subscriber to be notified on every reload. The
+// returned function unsubscribes the subscriber.
+func (w *Watcher[T]) Subscribe(fn Subscriber[T]) (unsubscribe func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	id := w.nextID
+	w.nextID++
+	w.subscribers[id] = fn
+
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		delete(w.subscribers, id)
+	}
+}
+
+// Domain returns the currently active domain config.
+func (w *Watcher[T]) Domain() *T {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.config.Domain
+}
+
+// Values returns the currently active parsed configuration values, see
+// Config.Values.
+func (w *Watcher[T]) Values() []ParsedConfig[T] {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.config.Values()
+}
+
+// Explain returns the provenance of the given domain config field in the
+// currently active configuration, see Config.Explain.
+func (w *Watcher[T]) Explain(field string) (Provenance, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.config.Explain(field)
+}
+
+// Stop stops watching and waits for any in-flight reload to finish.
+func (w *Watcher[T]) Stop() {
+	w.cancel()
+	<-w.done
+}
+
+// run polls the watched files until the context is done.
+func (w *Watcher[T]) run(ctx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			files := statFiles(&w.config.Options, w.filePaths())
+			if reflect.DeepEqual(files, w.files) {
+				continue
+			}
+
+			w.files = files
+			w.reload()
+		}
+	}
+}
+
+// filePaths returns the paths stat'ed during the last parse.
+func (w *Watcher[T]) filePaths() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.config.statted
+}
+
+// reload re-parses the configuration from the defaults, keeping the previous
+// domain config if the new configuration reports an error, then notifies
+// subscribers.
+func (w *Watcher[T]) reload() {
+	w.mu.Lock()
+	domain, diags := w.config.reload()
+	subscribers := make([]Subscriber[T], 0, len(w.subscribers))
+	for _, fn := range w.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	// pick up any newly discoverable files.
+	w.files = statFiles(&w.config.Options, w.config.statted)
+	w.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(domain, diags)
+	}
+}
+
+// reload re-parses the configuration into a fresh copy of the defaults. If
+// parsing reports an error, the previously parsed state is restored.
+func (c *Config[T]) reload() (*T, *diag.Diagnostics) {
+	prevDomain, prevParsed, prevProvenance := c.Domain, c.parsed, c.provenance
+
+	// the defaults are copied again, so that values merged in place into
+	// maps and slices don't leak into later reloads.
+	domain := deepCopyOf(*c.defaults)
+	c.Domain = &domain
+
+	// file parsers may leave stale values behind for keys that have since
+	// been removed from the file.
+	for _, fileConfig := range c.File {
+		resetFileValues(fileConfig)
+	}
+
+	_, diags := c.Parse()
+	if diags.HasFatal || diags.HasError {
+		diags.Append(diag.Diagnostic{
+			Severity: diag.SeverityWarn,
+			Code:     CodeReloadRejected,
+			Summary:  "Keeping previous configuration",
+			Detail:   "The reloaded configuration reported errors, so the previous configuration remains active",
+		})
+		c.Domain, c.parsed, c.provenance = prevDomain, prevParsed, prevProvenance
+	}
+
+	return c.Domain, diags
+}
+
+// statFiles captures the state of each of the provided file paths.
+func statFiles(opts *Options, paths []string) map[string]fileState {
+	files := make(map[string]fileState, len(paths))
+	for _, path := range paths {
+		info, err := opts.StatFile(path)
+		if err != nil {
+			files[path] = fileState{}
+			continue
+		}
+
+		files[path] = fileState{
+			exists:  true,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		}
+	}
+
+	return files
+}
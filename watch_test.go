@@ -0,0 +1,56 @@
+package configurator
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matthewhartstonge/configurator/diag"
+)
+
+func TestWatchReloadDropsRemovedMapKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"tags": {"team": "platform", "tier": "gold"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &Config[tagsDomain]{
+		Options: Options{
+			Args:            []string{"app"},
+			ConfigFilePaths: []string{path},
+		},
+		Domain: &tagsDomain{Tags: map[string]string{"env": "dev"}},
+		File:   []ConfigFileTypeable{newTestFile(&tagsConfig{})},
+	}
+	if _, diags := config.Parse(); diags.HasError {
+		t.Fatalf("Parse() reported errors: %v", diags.Err())
+	}
+
+	w := config.Watch(context.Background(), 10*time.Millisecond)
+	defer w.Stop()
+
+	reloaded := make(chan *tagsDomain, 1)
+	w.Subscribe(func(domain *tagsDomain, _ *diag.Diagnostics) {
+		select {
+		case reloaded <- domain:
+		default:
+		}
+	})
+
+	if err := os.WriteFile(path, []byte(`{"tags": {"team": "platform"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case domain := <-reloaded:
+		want := map[string]string{"env": "dev", "team": "platform"}
+		if !maps.Equal(domain.Tags, want) {
+			t.Errorf("reloaded Tags = %v, want %v", domain.Tags, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("config wasn't reloaded")
+	}
+}
@@ -0,0 +1,97 @@
+package configurator
+
+import (
+	"path/filepath"
+	"runtime"
+
+	"github.com/matthewhartstonge/configurator/diag"
+)
+
+const (
+	// XDG_CONFIG_HOME specifies the environment variable defining the base
+	// directory for user specific configuration files.
+	XDG_CONFIG_HOME = "XDG_CONFIG_HOME"
+	// XDG_CONFIG_DIRS specifies the environment variable defining the ordered
+	// set of base directories to search for configuration files, in addition
+	// to XDG_CONFIG_HOME.
+	XDG_CONFIG_DIRS = "XDG_CONFIG_DIRS"
+
+	// xdgDefaultConfigDirs is the XDG_CONFIG_DIRS default defined by the XDG
+	// Base Directory specification.
+	xdgDefaultConfigDirs = "/etc/xdg"
+)
+
+// isXDGPlatform reports if the XDG Base Directory specification is expected to
+// be followed on the current platform.
+func isXDGPlatform() bool {
+	switch runtime.GOOS {
+	case "windows", "darwin", "ios", "plan9", "js", "wasip1":
+		return false
+	default:
+		return true
+	}
+}
+
+// xdgConfigDirs returns the XDG_CONFIG_DIRS base directories, in order of
+// importance, most important first. If unset, the directories default to
+// /etc/xdg on platforms following the XDG specification.
+func xdgConfigDirs(diags *diag.Diagnostics, opts *Options) []string {
+	value, ok := opts.Environment.LookupEnv(XDG_CONFIG_DIRS)
+	if !ok || value == "" {
+		if !isXDGPlatform() {
+			diags.GlobalFile(XDG_CONFIG_DIRS).
+				Code(CodeXDGDirsUnset).
+				Trace("XDG Configuration Directories Not Set",
+					"XDG_CONFIG_DIRS is not set and the platform does not follow the XDG specification")
+			return nil
+		}
+
+		diags.GlobalFile(XDG_CONFIG_DIRS).
+			Code(CodeXDGDirsUnset).
+			Trace("XDG Configuration Directories Not Set",
+				"Defaulting XDG_CONFIG_DIRS to "+xdgDefaultConfigDirs)
+		value = xdgDefaultConfigDirs
+	}
+
+	var dirs []string
+	for _, dir := range filepath.SplitList(value) {
+		if dir == "" {
+			continue
+		}
+		if !filepath.IsAbs(dir) {
+			// The specification requires relative paths to be ignored.
+			diags.GlobalFile(XDG_CONFIG_DIRS).
+				Code(CodeXDGDirIgnored).
+				Trace("Ignoring Relative XDG Configuration Directory",
+					"XDG base directories must be absolute, but got "+dir)
+			continue
+		}
+
+		dirs = append(dirs, filepath.Clean(dir))
+	}
+
+	return dirs
+}
+
+// xdgConfigHome returns the XDG_CONFIG_HOME base directory, falling back to
+// the environment's user config directory if unset.
+func xdgConfigHome(diags *diag.Diagnostics, opts *Options) (string, error) {
+	dir, ok := opts.Environment.LookupEnv(XDG_CONFIG_HOME)
+	switch {
+	case !ok || dir == "":
+		diags.GlobalFile(XDG_CONFIG_HOME).
+			Code(CodeXDGHomeUnset).
+			Trace("XDG Configuration Home Not Set",
+				"Defaulting to the user configuration directory")
+	case !filepath.IsAbs(dir):
+		// The specification requires relative paths to be ignored.
+		diags.GlobalFile(XDG_CONFIG_HOME).
+			Code(CodeXDGDirIgnored).
+			Trace("Ignoring Relative XDG Configuration Home",
+				"XDG base directories must be absolute, but got "+dir)
+	default:
+		return filepath.Clean(dir), nil
+	}
+
+	return opts.Environment.UserConfigDir()
+}